@@ -0,0 +1,416 @@
+// Package alert evaluates configured rules against each snapshot and notifies
+// when an alert fires or resolves.
+package alert
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// Rule types
+const (
+	SoeBelow      = "soe_below"
+	GridFault     = "grid_fault"
+	NoPoll        = "no_poll"
+	BlockDisabled = "block_disabled"
+	NoSolar       = "no_solar"
+	Template      = "template"
+)
+
+// Alert states
+const (
+	Pending  = "pending"
+	Firing   = "firing"
+	Resolved = "resolved"
+)
+
+// Alert is one instance of a rule, such as a rule for a single battery block
+type Alert struct {
+	Rule       string    `json:"rule"`
+	Key        string    `json:"key,omitempty"`
+	Severity   string    `json:"severity,omitempty"`
+	State      string    `json:"state"`
+	Summary    string    `json:"summary"`
+	Since      time.Time `json:"since"`
+	FiredAt    time.Time `json:"fired_at"`
+	ClearSince time.Time `json:"clear_since"`
+	ResolvedAt time.Time `json:"resolved_at"`
+	Notified   time.Time `json:"notified"`
+}
+
+// ID identifies the alert among those of every rule
+func (a Alert) ID() string {
+	if a.Key == "" {
+		return a.Rule
+	}
+	return a.Rule + "/" + a.Key
+}
+
+// state is what the Engine persists across restarts, so an alert which was
+// firing is not notified again
+type state struct {
+	Alerts    map[string]*Alert `json:"alerts"`
+	Baselined bool              `json:"baselined"`
+	Faults    []string          `json:"faults"`
+}
+
+type rule struct {
+	conf       config.AlertRule
+	start, end int
+	condition  *template.Template
+	notifiers  []*notifier
+}
+
+// Engine holds the state of every alert
+type Engine struct {
+	conf      *config.Configuration
+	path      string
+	rules     []*rule
+	notifiers []*notifier
+	state     state
+	lastPoll  time.Time
+	site      string
+	dirty     bool
+	closed    bool
+	mu        sync.Mutex
+
+	wg      sync.WaitGroup
+	errCh   chan error
+	errRead int32
+}
+
+// parseClock reads HH:MM as minutes since midnight; 24:00 is the end of the
+// day
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("error when parsing time of day %s, %s", value, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// New validates the rules and notifiers, loads the alerts which were pending
+// or firing when the collector stopped and starts delivering notifications
+func New(conf *config.Configuration) (*Engine, error) {
+	e := &Engine{
+		conf:     conf,
+		path:     conf.Alerting.StateFile,
+		state:    state{Alerts: map[string]*Alert{}},
+		lastPoll: time.Now(),
+		errCh:    make(chan error),
+	}
+
+	byName := map[string]*notifier{}
+	for i, notifierConf := range conf.Alerting.Notifiers {
+		n, err := newNotifier(notifierConf)
+		if err != nil {
+			return nil, fmt.Errorf("error when configuring notifier %d, %s", i, err)
+		}
+		if byName[n.conf.Name] != nil {
+			return nil, fmt.Errorf("notifier %s is defined more than once", n.conf.Name)
+		}
+		byName[n.conf.Name] = n
+		e.notifiers = append(e.notifiers, n)
+	}
+
+	names := map[string]bool{}
+	for i, ruleConf := range conf.Alerting.Rules {
+		if ruleConf.Name == "" {
+			return nil, fmt.Errorf("alert rule %d has no name", i)
+		}
+		if names[ruleConf.Name] {
+			return nil, fmt.Errorf("alert rule %s is defined more than once", ruleConf.Name)
+		}
+		names[ruleConf.Name] = true
+
+		r := &rule{conf: ruleConf}
+		var err error
+		switch ruleConf.Type {
+		case SoeBelow, GridFault, BlockDisabled:
+		case NoPoll:
+			if r.conf.After == 0 {
+				r.conf.After = 5 * time.Minute
+			}
+		case NoSolar:
+			if r.conf.Start == "" {
+				r.conf.Start = "11:00"
+			}
+			if r.conf.End == "" {
+				r.conf.End = "14:00"
+			}
+			r.start, err = parseClock(r.conf.Start)
+			if err == nil {
+				r.end, err = parseClock(r.conf.End)
+			}
+		case Template:
+			r.condition, err = template.New(ruleConf.Name).Funcs(funcs).Parse(ruleConf.Condition)
+			if err != nil {
+				err = fmt.Errorf("error when parsing condition, %s", err)
+			}
+		default:
+			err = fmt.Errorf("unknown type %q", ruleConf.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error when configuring alert rule %s, %s", ruleConf.Name, err)
+		}
+
+		if len(ruleConf.Notifiers) == 0 {
+			r.notifiers = e.notifiers
+		}
+		for _, name := range ruleConf.Notifiers {
+			n := byName[name]
+			if n == nil {
+				return nil, fmt.Errorf("alert rule %s refers to unknown notifier %s", ruleConf.Name, name)
+			}
+			r.notifiers = append(r.notifiers, n)
+		}
+		e.rules = append(e.rules, r)
+	}
+
+	if e.path != "" {
+		err := energy.LoadState(e.path, &e.state)
+		if err != nil {
+			return nil, err
+		}
+		if e.state.Alerts == nil {
+			e.state.Alerts = map[string]*Alert{}
+		}
+	}
+
+	// Alerts of rules which have since been removed are dropped
+	for id, a := range e.state.Alerts {
+		if !names[a.Rule] {
+			delete(e.state.Alerts, id)
+		}
+	}
+
+	for _, n := range e.notifiers {
+		e.wg.Add(1)
+		go func(n *notifier) {
+			defer e.wg.Done()
+			for note := range n.queue {
+				err := n.deliver(note)
+				if err != nil {
+					e.reportError(err)
+				}
+			}
+		}(n)
+	}
+
+	return e, nil
+}
+
+// Alerts lists the alerts which are pending or firing, sorted by ID
+func (e *Engine) Alerts() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var alerts []Alert
+	for _, a := range e.state.Alerts {
+		alerts = append(alerts, *a)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].ID() < alerts[j].ID()
+	})
+	return alerts
+}
+
+// Update evaluates the rules against a snapshot taken at now and returns the
+// alerts which changed state
+func (e *Engine) Update(metrics model.Teg, now time.Time) ([]Alert, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, nil
+	}
+	e.lastPoll = now
+	if metrics.SiteInfo.SiteName != "" {
+		e.site = metrics.SiteInfo.SiteName
+	}
+
+	var changes []Alert
+	var errs []error
+	for _, r := range e.rules {
+		if r.conf.Type == NoPoll {
+			continue
+		}
+		active, err := e.check(r, metrics)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error when evaluating alert rule %s, %s", r.conf.Name, err))
+			continue
+		}
+		changes = append(changes, e.evaluate(r, active, now)...)
+	}
+
+	err := e.save(changes)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return changes, errs[0]
+	}
+	return changes, nil
+}
+
+// Tick evaluates the rules which watch the polls themselves; it is called
+// after every poll, successful or not
+func (e *Engine) Tick(now time.Time) ([]Alert, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, nil
+	}
+	var changes []Alert
+	for _, r := range e.rules {
+		if r.conf.Type != NoPoll {
+			continue
+		}
+		active := map[string]string{}
+		if since := now.Sub(e.lastPoll); since >= r.conf.After {
+			active[""] = fmt.Sprintf("no successful poll for %s", since.Round(time.Second))
+		}
+		changes = append(changes, e.evaluate(r, active, now)...)
+	}
+	return changes, e.save(changes)
+}
+
+// evaluate moves the alerts of a rule between states given the instances for
+// which its condition holds, with their summaries, and notifies on firing,
+// repeating and resolving
+func (e *Engine) evaluate(r *rule, active map[string]string, now time.Time) []Alert {
+	var changes []Alert
+
+	keys := make([]string, 0, len(active))
+	for key := range active {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		id := Alert{Rule: r.conf.Name, Key: key}.ID()
+		a := e.state.Alerts[id]
+		if a == nil {
+			a = &Alert{
+				Rule:     r.conf.Name,
+				Key:      key,
+				Severity: r.conf.Severity,
+				State:    Pending,
+				Since:    now,
+			}
+			e.state.Alerts[id] = a
+			if r.conf.For > 0 {
+				a.Summary = active[key]
+				changes = append(changes, *a)
+			}
+		}
+		a.Summary = active[key]
+		a.ClearSince = time.Time{}
+
+		switch {
+		case a.State == Pending && now.Sub(a.Since) >= r.conf.For:
+			a.State = Firing
+			a.FiredAt = now
+			a.Notified = now
+			e.notify(r, *a, false)
+			changes = append(changes, *a)
+		case a.State == Firing && r.conf.Repeat > 0 && now.Sub(a.Notified) >= r.conf.Repeat:
+			a.Notified = now
+			e.notify(r, *a, true)
+		}
+	}
+
+	for id, a := range e.state.Alerts {
+		if a.Rule != r.conf.Name {
+			continue
+		}
+		if _, ok := active[a.Key]; ok {
+			continue
+		}
+		if a.State == Pending {
+			delete(e.state.Alerts, id)
+			continue
+		}
+		if a.ClearSince.IsZero() {
+			a.ClearSince = now
+		}
+		if now.Sub(a.ClearSince) < r.conf.ClearFor {
+			continue
+		}
+		delete(e.state.Alerts, id)
+		a.State = Resolved
+		a.ResolvedAt = now
+		if !r.conf.SkipResolved {
+			e.notify(r, *a, false)
+		}
+		changes = append(changes, *a)
+	}
+
+	return changes
+}
+
+// notify queues the notification for each notifier of the rule; a notifier
+// which has fallen too far behind drops it
+func (e *Engine) notify(r *rule, a Alert, repeat bool) {
+	note := Notification{Alert: a, Site: e.site, Repeat: repeat}
+	for _, n := range r.notifiers {
+		select {
+		case n.queue <- note:
+		default:
+			e.reportError(fmt.Errorf("error when queueing notification for %s, queue is full", n.conf.Name))
+		}
+	}
+}
+
+// save persists the alerts when any changed state, or the known grid faults
+// when they changed
+func (e *Engine) save(changes []Alert) error {
+	if e.path == "" || (len(changes) == 0 && !e.dirty) {
+		return nil
+	}
+	e.dirty = false
+	return energy.SaveState(e.path, e.state)
+}
+
+// Close saves the alerts and waits for queued notifications to be delivered
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	for _, n := range e.notifiers {
+		close(n.queue)
+	}
+	var err error
+	if e.path != "" {
+		err = energy.SaveState(e.path, e.state)
+	}
+	e.mu.Unlock()
+
+	// Deliveries retry with backoff, so wait for them without holding up
+	// Alerts, Update and Tick
+	e.wg.Wait()
+	return err
+}
+
+// Errors returns the channel of evaluation and delivery errors
+func (e *Engine) Errors() <-chan error {
+	atomic.StoreInt32(&e.errRead, 1)
+	return e.errCh
+}
+
+func (e *Engine) reportError(err error) {
+	if atomic.LoadInt32(&e.errRead) == 1 {
+		e.errCh <- err
+	}
+}
@@ -0,0 +1,179 @@
+package alert
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+)
+
+// states lists the states of the changes an update returned
+func states(changes []Alert) []string {
+	var s []string
+	for _, a := range changes {
+		s = append(s, a.State)
+	}
+	return s
+}
+
+func expectStates(t *testing.T, step string, changes []Alert, expected ...string) {
+	t.Helper()
+	got := states(changes)
+	if len(got) != len(expected) {
+		t.Fatalf("%s: expected changes %v but got %v", step, expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("%s: expected changes %v but got %v", step, expected, got)
+		}
+	}
+}
+
+func TestPendingFiringResolved(t *testing.T) {
+	s := newWebhookServer(t)
+	conf := lowBattery(config.Notifier{Type: Ntfy, URL: s.URL})
+	conf.Alerting.Rules[0].For = 2 * time.Minute
+	conf.Alerting.Rules[0].ClearFor = time.Minute
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	changes, _ := e.Update(soe(at(0), 15), at(0))
+	expectStates(t, "low", changes, Pending)
+	changes, _ = e.Update(soe(at(time.Minute), 15), at(time.Minute))
+	expectStates(t, "still low within for", changes)
+	changes, _ = e.Update(soe(at(2*time.Minute), 15), at(2*time.Minute))
+	expectStates(t, "low for 2m", changes, Firing)
+
+	// Clearing only resolves the alert once it has stayed clear for ClearFor
+	changes, _ = e.Update(soe(at(3*time.Minute), 30), at(3*time.Minute))
+	expectStates(t, "clear", changes)
+	changes, _ = e.Update(soe(at(3*time.Minute+30*time.Second), 15), at(3*time.Minute+30*time.Second))
+	expectStates(t, "low again within clearFor", changes)
+	changes, _ = e.Update(soe(at(4*time.Minute), 30), at(4*time.Minute))
+	expectStates(t, "clear again", changes)
+	if alerts := e.Alerts(); len(alerts) != 1 || alerts[0].State != Firing {
+		t.Fatalf("expected the alert to keep firing while clearing but got %v", alerts)
+	}
+	changes, _ = e.Update(soe(at(5*time.Minute), 30), at(5*time.Minute))
+	expectStates(t, "clear for 1m", changes, Resolved)
+	if !changes[0].FiredAt.Equal(at(2*time.Minute)) || !changes[0].ResolvedAt.Equal(at(5*time.Minute)) {
+		t.Errorf("unexpected resolution %+v", changes[0])
+	}
+	if alerts := e.Alerts(); len(alerts) != 0 {
+		t.Errorf("expected no alerts once resolved but got %v", alerts)
+	}
+
+	e.Close()
+	if len(s.requests) != 2 {
+		t.Errorf("expected only the firing and resolved notifications but got %d", len(s.requests))
+	}
+}
+
+func TestPendingClearsWithoutNotifying(t *testing.T) {
+	s := newWebhookServer(t)
+	conf := lowBattery(config.Notifier{Type: Ntfy, URL: s.URL})
+	conf.Alerting.Rules[0].For = 5 * time.Minute
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	changes, _ := e.Update(soe(now, 15), now)
+	expectStates(t, "low", changes, Pending)
+	now = now.Add(time.Minute)
+	changes, _ = e.Update(soe(now, 30), now)
+	expectStates(t, "clear before for", changes)
+	if alerts := e.Alerts(); len(alerts) != 0 {
+		t.Errorf("expected the pending alert to be dropped but got %v", alerts)
+	}
+
+	e.Close()
+	if len(s.requests) != 0 {
+		t.Errorf("expected no notifications but got %d", len(s.requests))
+	}
+}
+
+func TestRepeatsWhileFiring(t *testing.T) {
+	s := newWebhookServer(t)
+	conf := lowBattery(config.Notifier{Type: Ntfy, URL: s.URL})
+	conf.Alerting.Rules[0].Repeat = time.Hour
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	for i := 0; i <= 4; i++ {
+		at := now.Add(time.Duration(i) * 30 * time.Minute)
+		e.Update(soe(at, 10), at)
+	}
+	e.Close()
+
+	if len(s.requests) != 3 {
+		t.Fatalf("expected the firing notification and two repeats but got %d", len(s.requests))
+	}
+	if title := s.requests[1].header.Get("Title"); title != "[STILL FIRING] low_battery (home)" {
+		t.Errorf("unexpected repeat title %q", title)
+	}
+}
+
+func TestNoPoll(t *testing.T) {
+	conf := &config.Configuration{}
+	conf.Alerting.Rules = []config.AlertRule{{Name: "stale", Type: NoPoll, After: 5 * time.Minute}}
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e.Update(soe(now, 50), now)
+	changes, _ := e.Tick(now.Add(4 * time.Minute))
+	expectStates(t, "polled 4m ago", changes)
+	changes, _ = e.Tick(now.Add(6 * time.Minute))
+	expectStates(t, "polled 6m ago", changes, Firing)
+	if changes[0].Summary != "no successful poll for 6m0s" {
+		t.Errorf("unexpected summary %q", changes[0].Summary)
+	}
+	now = now.Add(7 * time.Minute)
+	e.Update(soe(now, 50), now)
+	changes, _ = e.Tick(now)
+	expectStates(t, "polled again", changes, Resolved)
+}
+
+func TestFiringSurvivesRestart(t *testing.T) {
+	s := newWebhookServer(t)
+	conf := lowBattery(config.Notifier{Type: Ntfy, URL: s.URL})
+	conf.Alerting.StateFile = filepath.Join(t.TempDir(), "alerts.json")
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Update(soe(now, 10), now)
+	e.Close()
+
+	e, err = New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	changes, _ := e.Update(soe(now, 10), now)
+	expectStates(t, "still low after restart", changes)
+	now = now.Add(time.Minute)
+	changes, _ = e.Update(soe(now, 50), now)
+	expectStates(t, "clear after restart", changes, Resolved)
+	e.Close()
+
+	if len(s.requests) != 2 {
+		t.Errorf("expected one firing and one resolved notification across the restart but got %d", len(s.requests))
+	}
+}
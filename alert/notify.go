@@ -0,0 +1,275 @@
+package alert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notifier types
+const (
+	SMTP     = "smtp"
+	Ntfy     = "ntfy"
+	Slack    = "slack"
+	Pushover = "pushover"
+)
+
+// pushoverURL is the Pushover messages API
+const pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// queueSize is how many notifications may wait for a notifier
+const queueSize = 64
+
+// Notification is an alert which fired, is still firing or resolved
+type Notification struct {
+	Alert
+	Site   string
+	Repeat bool
+}
+
+// Title is a one line description of the notification
+func (n Notification) Title() string {
+	state := strings.ToUpper(n.State)
+	if n.Repeat {
+		state = "STILL " + state
+	}
+	title := fmt.Sprintf("[%s] %s", state, n.Rule)
+	if n.Key != "" {
+		title += " " + n.Key
+	}
+	if n.Site != "" {
+		title += " (" + n.Site + ")"
+	}
+	return title
+}
+
+// Message describes the alert and how long it has been firing
+func (n Notification) Message() string {
+	var b strings.Builder
+	b.WriteString(n.Summary)
+	if n.Severity != "" {
+		fmt.Fprintf(&b, "\nseverity: %s", n.Severity)
+	}
+	fmt.Fprintf(&b, "\nsince: %s", n.Since.Format(time.RFC3339))
+	if n.State == Resolved {
+		fmt.Fprintf(&b, "\nresolved: %s after %s", n.ResolvedAt.Format(time.RFC3339),
+			n.ResolvedAt.Sub(n.FiredAt).Round(time.Second))
+	}
+	return b.String()
+}
+
+type notifier struct {
+	conf   config.Notifier
+	client *http.Client
+	queue  chan Notification
+}
+
+// newNotifier checks that a notifier has the parameters its type needs
+func newNotifier(conf config.Notifier) (*notifier, error) {
+	if conf.Name == "" {
+		conf.Name = conf.Type
+	}
+	if conf.Timeout == 0 {
+		conf.Timeout = 10 * time.Second
+	}
+
+	switch conf.Type {
+	case SMTP:
+		if conf.Host == "" || conf.From == "" || len(conf.To) == 0 {
+			return nil, fmt.Errorf("%s needs host, from and to", conf.Name)
+		}
+		if _, _, err := net.SplitHostPort(conf.Host); err != nil {
+			return nil, fmt.Errorf("error when parsing host of %s, %s", conf.Name, err)
+		}
+	case Ntfy, Slack:
+		if conf.URL == "" {
+			return nil, fmt.Errorf("%s needs a URL", conf.Name)
+		}
+	case Pushover:
+		if conf.Token == "" || conf.User == "" {
+			return nil, fmt.Errorf("%s needs a token and user", conf.Name)
+		}
+		if conf.URL == "" {
+			conf.URL = pushoverURL
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q for %s", conf.Type, conf.Name)
+	}
+
+	return &notifier{
+		conf: conf,
+		client: &http.Client{
+			Timeout: conf.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: conf.SkipVerifySsl},
+			},
+		},
+		queue: make(chan Notification, queueSize),
+	}, nil
+}
+
+// deliver sends the notification, retrying connection errors, 429 and 5xx
+// responses with backoff
+func (n *notifier) deliver(note Notification) error {
+	backoff := time.Second
+	var err error
+	for attempt := uint(0); ; attempt++ {
+		var retry bool
+		retry, err = n.send(note)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.conf.Retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return fmt.Errorf("error when notifying %s of %s, %s", n.conf.Name, note.ID(), err)
+}
+
+func (n *notifier) send(note Notification) (bool, error) {
+	switch n.conf.Type {
+	case SMTP:
+		return n.sendMail(note)
+	case Ntfy:
+		req, err := http.NewRequest(http.MethodPost, n.conf.URL, strings.NewReader(note.Message()))
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Title", note.Title())
+		if n.conf.Priority != 0 {
+			req.Header.Set("Priority", strconv.Itoa(n.conf.Priority))
+		}
+		if note.State == Resolved {
+			req.Header.Set("Tags", "white_check_mark")
+		} else {
+			req.Header.Set("Tags", "warning")
+		}
+		if n.conf.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+n.conf.Token)
+		}
+		return n.do(req)
+	case Slack:
+		payload, err := json.Marshal(map[string]string{
+			"text": "*" + note.Title() + "*\n" + note.Message(),
+		})
+		if err != nil {
+			return false, err
+		}
+		req, err := http.NewRequest(http.MethodPost, n.conf.URL, bytes.NewReader(payload))
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return n.do(req)
+	case Pushover:
+		form := url.Values{
+			"token":     {n.conf.Token},
+			"user":      {n.conf.User},
+			"title":     {note.Title()},
+			"message":   {note.Message()},
+			"priority":  {strconv.Itoa(n.conf.Priority)},
+			"timestamp": {strconv.FormatInt(time.Now().Unix(), 10)},
+		}
+		req, err := http.NewRequest(http.MethodPost, n.conf.URL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return n.do(req)
+	}
+	return false, fmt.Errorf("unknown type %q", n.conf.Type)
+}
+
+func (n *notifier) do(req *http.Request) (bool, error) {
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("expected 2xx HTTP status code but got %d; raw body %s", resp.StatusCode, body)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+
+	return false, nil
+}
+
+// sendMail sends the notification by SMTP, upgrading to TLS when the server
+// offers STARTTLS; credentials are only sent over TLS or to localhost
+func (n *notifier) sendMail(note Notification) (bool, error) {
+	host, _, _ := net.SplitHostPort(n.conf.Host)
+	conn, err := net.DialTimeout("tcp", n.conf.Host, n.conf.Timeout)
+	if err != nil {
+		return true, err
+	}
+	conn.SetDeadline(time.Now().Add(n.conf.Timeout))
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return true, err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		err = c.StartTLS(&tls.Config{ServerName: host, InsecureSkipVerify: n.conf.SkipVerifySsl})
+		if err != nil {
+			return true, err
+		}
+	}
+	if n.conf.Username != "" {
+		err = c.Auth(smtp.PlainAuth("", n.conf.Username, n.conf.Password, host))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = c.Mail(n.conf.From)
+	if err != nil {
+		return true, err
+	}
+	for _, to := range n.conf.To {
+		err = c.Rcpt(to)
+		if err != nil {
+			return true, err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return true, err
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.conf.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.conf.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", note.Title()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(note.Message(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	_, err = w.Write(msg.Bytes())
+	if err != nil {
+		return true, err
+	}
+	err = w.Close()
+	if err != nil {
+		return true, err
+	}
+	return false, c.Quit()
+}
@@ -0,0 +1,294 @@
+package alert
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+)
+
+// request is a notification received by a stand-in service
+type request struct {
+	header http.Header
+	body   string
+}
+
+// webhookServer is a stand-in for an HTTP notification service which answers
+// with the given status codes in turn, then 200
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []request
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, request{header: r.Header, body: string(body)})
+		if len(s.statuses) > 0 {
+			status := s.statuses[0]
+			s.statuses = s.statuses[1:]
+			w.WriteHeader(status)
+			w.Write([]byte("unavailable"))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// smtpServer is a stand-in mail server speaking just enough SMTP to take a
+// message
+type smtpServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	from     string
+	rcpt     []string
+	messages []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = line
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, line)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			var msg strings.Builder
+			for {
+				data, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if data == ".\r\n" {
+					break
+				}
+				msg.WriteString(data)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+// lowBattery is a rule which fires straight away, so a firing and a resolved
+// notification follow from two snapshots
+func lowBattery(notifiers ...config.Notifier) *config.Configuration {
+	conf := &config.Configuration{}
+	conf.Alerting.Rules = []config.AlertRule{{Name: "low_battery", Type: SoeBelow, Threshold: 20, Severity: "warning"}}
+	conf.Alerting.Notifiers = notifiers
+	return conf
+}
+
+func soe(ts time.Time, percentage float64) model.Teg {
+	var metrics model.Teg
+	metrics.SiteInfo.SiteName = "home"
+	metrics.SystemStateOfEnergy.Timestamp = ts
+	metrics.SystemStateOfEnergy.Percentage = percentage
+	return metrics
+}
+
+// fireAndResolve runs an alert through firing and resolving and waits for
+// the notifications to be delivered
+func fireAndResolve(t *testing.T, conf *config.Configuration) {
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	_, err = e.Update(soe(now, 12.5), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	_, err = e.Update(soe(now, 40), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	s := newWebhookServer(t)
+	fireAndResolve(t, lowBattery(config.Notifier{Name: "phone", Type: Ntfy, URL: s.URL + "/solar", Token: "tk", Priority: 4}))
+
+	if len(s.requests) != 2 {
+		t.Fatalf("expected a firing and a resolved notification but got %d", len(s.requests))
+	}
+	firing, resolved := s.requests[0], s.requests[1]
+	if title := firing.header.Get("Title"); title != "[FIRING] low_battery (home)" {
+		t.Errorf("unexpected title %q", title)
+	}
+	if !strings.HasPrefix(firing.body, "state of energy 12.5% is below 20%\nseverity: warning") {
+		t.Errorf("unexpected message %q", firing.body)
+	}
+	if firing.header.Get("Tags") != "warning" || firing.header.Get("Priority") != "4" || firing.header.Get("Authorization") != "Bearer tk" {
+		t.Errorf("unexpected headers %v", firing.header)
+	}
+	if title := resolved.header.Get("Title"); title != "[RESOLVED] low_battery (home)" {
+		t.Errorf("unexpected title %q", title)
+	}
+	if resolved.header.Get("Tags") != "white_check_mark" || !strings.Contains(resolved.body, "resolved: 2026-05-01T21:00:00Z after 1h0m0s") {
+		t.Errorf("unexpected resolution %v %q", resolved.header, resolved.body)
+	}
+}
+
+func TestSlackRetries(t *testing.T) {
+	s := newWebhookServer(t, http.StatusServiceUnavailable)
+	conf := lowBattery(config.Notifier{Type: Slack, URL: s.URL, Retries: 1})
+	conf.Alerting.Rules[0].SkipResolved = true
+	fireAndResolve(t, conf)
+
+	if len(s.requests) != 2 {
+		t.Fatalf("expected the firing notification to be retried once but got %d requests", len(s.requests))
+	}
+	for _, r := range s.requests {
+		var payload map[string]string
+		err := json.Unmarshal([]byte(r.body), &payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(payload["text"], "*[FIRING] low_battery (home)*\nstate of energy 12.5%") {
+			t.Errorf("unexpected text %q", payload["text"])
+		}
+	}
+}
+
+func TestSlackGivesUpOnClientErrors(t *testing.T) {
+	s := newWebhookServer(t, http.StatusBadRequest, http.StatusBadRequest)
+	conf := lowBattery(config.Notifier{Type: Slack, URL: s.URL, Retries: 3})
+	e, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := e.Errors()
+	done := make(chan error)
+	go func() {
+		done <- <-errs
+	}()
+
+	now := time.Now()
+	e.Update(soe(now, 5), now)
+	err = <-done
+	if err == nil || !strings.Contains(err.Error(), "got 400") {
+		t.Errorf("expected a delivery error but got %v", err)
+	}
+	e.Close()
+
+	if len(s.requests) != 1 {
+		t.Errorf("expected no retry of a rejected notification but got %d requests", len(s.requests))
+	}
+}
+
+func TestPushover(t *testing.T) {
+	s := newWebhookServer(t)
+	fireAndResolve(t, lowBattery(config.Notifier{Type: Pushover, URL: s.URL, Token: "app", User: "me", Priority: 1}))
+
+	if len(s.requests) != 2 {
+		t.Fatalf("expected a firing and a resolved notification but got %d", len(s.requests))
+	}
+	for i, title := range []string{"[FIRING] low_battery (home)", "[RESOLVED] low_battery (home)"} {
+		form, err := url.ParseQuery(s.requests[i].body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("token") != "app" || form.Get("user") != "me" || form.Get("priority") != "1" {
+			t.Errorf("unexpected form %v", form)
+		}
+		if form.Get("title") != title {
+			t.Errorf("expected title %q but got %q", title, form.Get("title"))
+		}
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	s := newSMTPServer(t)
+	fireAndResolve(t, lowBattery(config.Notifier{
+		Type: SMTP,
+		Host: s.ln.Addr().String(),
+		From: "collector@example.com",
+		To:   []string{"me@example.com", "you@example.com"},
+	}))
+
+	if len(s.messages) != 2 {
+		t.Fatalf("expected a firing and a resolved message but got %d", len(s.messages))
+	}
+	if s.from != "MAIL FROM:<collector@example.com>" || len(s.rcpt) != 4 || s.rcpt[1] != "RCPT TO:<you@example.com>" {
+		t.Errorf("unexpected envelope %s %v", s.from, s.rcpt)
+	}
+	for i, subject := range []string{"Subject: [FIRING] low_battery (home)\r\n", "Subject: [RESOLVED] low_battery (home)\r\n"} {
+		msg := s.messages[i]
+		if !strings.Contains(msg, subject) || !strings.Contains(msg, "To: me@example.com, you@example.com\r\n") {
+			t.Errorf("unexpected message %q", msg)
+		}
+		if !strings.Contains(msg, "\r\n\r\nstate of energy") {
+			t.Errorf("expected the summary in the body of %q", msg)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package alert
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/outage"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// funcs are available to template conditions
+var funcs = template.FuncMap{
+	"islanded": outage.Islanded,
+}
+
+// check returns the instances of a rule whose condition holds in a snapshot,
+// keyed by instance with a summary of each
+func (e *Engine) check(r *rule, metrics model.Teg) (map[string]string, error) {
+	active := map[string]string{}
+
+	switch r.conf.Type {
+	case SoeBelow:
+		soe := metrics.SystemStateOfEnergy
+		if soe.Timestamp.IsZero() || soe.Percentage >= r.conf.Threshold {
+			break
+		}
+		if !r.conf.GridDown {
+			active[""] = fmt.Sprintf("state of energy %.1f%% is below %g%%", soe.Percentage, r.conf.Threshold)
+		} else if outage.Islanded(metrics) {
+			active[""] = fmt.Sprintf("state of energy %.1f%% is below %g%% while the grid is down", soe.Percentage, r.conf.Threshold)
+		}
+
+	case GridFault:
+		for key, fault := range e.newFaults(metrics) {
+			active[key] = fmt.Sprintf("grid fault %s at %s", fault.AlertName,
+				time.UnixMilli(int64(fault.Timestamp)).Format(time.RFC3339))
+		}
+
+	case BlockDisabled:
+		for _, block := range metrics.SystemStatus.BatteryBlocks {
+			if len(block.DisabledReasons) == 0 {
+				continue
+			}
+			active[block.PackageSerialNumber] = fmt.Sprintf("battery block %s is disabled: %s",
+				block.PackageSerialNumber, strings.Join(block.DisabledReasons, ", "))
+		}
+
+	case NoSolar:
+		if metrics.Meters.Timestamp.IsZero() {
+			break
+		}
+		loc, err := energy.Location(e.conf.Energy.Timezone, metrics)
+		if err != nil {
+			return nil, err
+		}
+		local := metrics.Meters.Timestamp.In(loc)
+		minute := local.Hour()*60 + local.Minute()
+		solar := metrics.Meters.Solar.InstantPowerWatts
+		if minute >= r.start && minute < r.end && solar <= r.conf.Threshold {
+			active[""] = fmt.Sprintf("solar power is %.0f W at %s", solar, local.Format("15:04"))
+		}
+
+	case Template:
+		var out bytes.Buffer
+		err := r.condition.Execute(&out, metrics)
+		if err != nil {
+			return nil, fmt.Errorf("error when rendering condition, %s", err)
+		}
+		if strings.TrimSpace(out.String()) == "true" {
+			active[""] = fmt.Sprintf("%s condition holds", r.conf.Name)
+		}
+	}
+
+	return active, nil
+}
+
+// newFaults returns the grid faults which appeared since the collector first
+// saw the gateway's list of faults, keyed by name and time. Faults already
+// listed then are ignored, as are faults once the gateway drops them.
+func (e *Engine) newFaults(metrics model.Teg) map[string]model.TegGridFault {
+	current := map[string]model.TegGridFault{}
+	for _, fault := range metrics.SystemStatus.GridFaults {
+		current[fmt.Sprintf("%s@%d", fault.AlertName, fault.Timestamp)] = fault
+	}
+
+	if !e.state.Baselined {
+		e.state.Baselined = true
+		e.state.Faults = nil
+		for key := range current {
+			e.state.Faults = append(e.state.Faults, key)
+		}
+		e.dirty = true
+	}
+
+	var known []string
+	for _, key := range e.state.Faults {
+		if _, ok := current[key]; ok {
+			known = append(known, key)
+			delete(current, key)
+		} else {
+			e.dirty = true
+		}
+	}
+	e.state.Faults = known
+
+	return current
+}
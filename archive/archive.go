@@ -0,0 +1,486 @@
+// Package archive appends polled points to CSV or Parquet files, one file
+// per measurement per period of the configured directory layout, for loading
+// history into tools such as pandas or DuckDB.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	lp "github.com/influxdata/line-protocol"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	pqgzip "github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// defaultLayout puts each site's files in a directory per day
+const defaultLayout = "{{.Site}}/{{.Date}}/{{.Measurement}}"
+
+// Defaults bounding how many Parquet rows are held in memory, and how much is
+// lost if the collector is killed without a clean shutdown
+const (
+	defaultRotateInterval = time.Hour
+	defaultRowGroupSize   = 10000
+)
+
+// layoutData is available to the layout template
+type layoutData struct {
+	Site        string
+	Measurement string
+	Date        string
+	Year        string
+	Month       string
+	Day         string
+	Hour        string
+}
+
+// file is an open CSV or Parquet file for one measurement and site
+type file struct {
+	path   string
+	schema *schema
+	f      *os.File
+	gz     *gzip.Writer
+	csv    *csv.Writer
+	pq     *parquet.Writer
+	// final is the name a Parquet file is renamed to once it is complete
+	final string
+	// opened and rows are when a Parquet file was opened and how many rows
+	// are buffered for its next row group
+	opened time.Time
+	rows   int
+}
+
+// Writer is a WriteAPI which appends points to CSV or Parquet files
+type Writer struct {
+	directory    string
+	format       string
+	compression  string
+	codec        compress.Codec
+	layout       *template.Template
+	measurements map[string]bool
+	rotate       time.Duration
+	rowGroupSize int
+
+	mu      sync.Mutex
+	schemas map[string]*schema
+	files   map[string]*file
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	errCh   chan error
+	errRead int32
+}
+
+// Open prepares the archive directory and starts flushing files periodically
+func Open(conf *config.Configuration) (*Writer, error) {
+	w := &Writer{
+		directory:    conf.Archive.Directory,
+		format:       conf.Archive.Format,
+		rotate:       conf.Archive.RotateInterval,
+		rowGroupSize: conf.Archive.RowGroupSize,
+		schemas:      knownSchemas(conf),
+		files:        map[string]*file{},
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+		errCh:        make(chan error),
+	}
+	if w.rotate == 0 {
+		w.rotate = defaultRotateInterval
+	}
+	if w.rowGroupSize == 0 {
+		w.rowGroupSize = defaultRowGroupSize
+	}
+
+	if w.format == "" {
+		w.format = "csv"
+	}
+	switch w.format {
+	case "csv":
+		switch conf.Archive.Compression {
+		case "", "none":
+		case "gzip":
+			w.compression = "gzip"
+		default:
+			return nil, fmt.Errorf("unsupported CSV compression %s, must be one of none or gzip", conf.Archive.Compression)
+		}
+	case "parquet":
+		switch conf.Archive.Compression {
+		case "", "none":
+		case "snappy":
+			w.codec = &snappy.Codec{}
+		case "gzip":
+			w.codec = &pqgzip.Codec{}
+		case "zstd":
+			w.codec = &zstd.Codec{}
+		default:
+			return nil, fmt.Errorf("unsupported Parquet compression %s, must be one of none, snappy, gzip or zstd", conf.Archive.Compression)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %s, must be one of csv or parquet", w.format)
+	}
+
+	layout := conf.Archive.Layout
+	if layout == "" {
+		layout = defaultLayout
+	}
+	var err error
+	w.layout, err = template.New("layout").Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing layout, %s", err)
+	}
+
+	if len(conf.Archive.Measurements) > 0 {
+		w.measurements = map[string]bool{}
+		for _, m := range conf.Archive.Measurements {
+			w.measurements[conf.InfluxDB.MeasurementPrefix+m] = true
+		}
+	}
+
+	err = os.MkdirAll(w.directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Archive.FlushInterval == 0 {
+		conf.Archive.FlushInterval = 10
+	}
+	go w.run(time.Duration(conf.Archive.FlushInterval) * time.Second)
+
+	return w, nil
+}
+
+func (w *Writer) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(false)
+		case <-w.stopCh:
+			close(w.doneCh)
+			return
+		}
+	}
+}
+
+// Close closes every open file, completing any Parquet files
+func (w *Writer) Close() {
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, f := range w.files {
+		w.closeFile(f)
+		delete(w.files, key)
+	}
+}
+
+// Flush writes buffered CSV rows to disk and Parquet rows out as a row group
+func (w *Writer) Flush() {
+	w.flush(true)
+}
+
+// flush writes buffered CSV rows to disk; Parquet files open for longer than
+// the rotation interval are completed, so they are readable even if the
+// collector is later killed, and the next point starts a new numbered file
+func (w *Writer) flush(rowGroups bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, f := range w.files {
+		var err error
+		if f.csv != nil {
+			f.csv.Flush()
+			err = f.csv.Error()
+			if err == nil && f.gz != nil {
+				err = f.gz.Flush()
+			}
+		} else if time.Since(f.opened) >= w.rotate {
+			w.closeFile(f)
+			delete(w.files, key)
+		} else if rowGroups && f.rows > 0 {
+			err = f.pq.Flush()
+			f.rows = 0
+		}
+		if err != nil {
+			w.reportError(fmt.Errorf("error when flushing %s, %s", f.path, err))
+		}
+	}
+}
+
+// Errors returns the channel of file errors
+func (w *Writer) Errors() <-chan error {
+	atomic.StoreInt32(&w.errRead, 1)
+	return w.errCh
+}
+
+// SetWriteFailedCallback is a no-op, failed writes are reported on Errors
+func (w *Writer) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+}
+
+func (w *Writer) reportError(err error) {
+	if atomic.LoadInt32(&w.errRead) == 1 {
+		w.errCh <- err
+	}
+}
+
+// WriteRecord parses a line protocol record and writes its points
+func (w *Writer) WriteRecord(line string) {
+	metrics, err := lp.NewParser(lp.NewMetricHandler()).Parse([]byte(line))
+	if err != nil {
+		w.reportError(fmt.Errorf("error when parsing line protocol, %s", err))
+		return
+	}
+	for _, m := range metrics {
+		p := write.NewPointWithMeasurement(m.Name()).SetTime(m.Time())
+		for _, tag := range m.TagList() {
+			p.AddTag(tag.Key, tag.Value)
+		}
+		for _, field := range m.FieldList() {
+			p.AddField(field.Key, field.Value)
+		}
+		w.WritePoint(p)
+	}
+}
+
+// WritePoint appends a point as a row of its measurement's file
+func (w *Writer) WritePoint(point *write.Point) {
+	if w.measurements != nil && !w.measurements[point.Name()] {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, ok := w.schemas[point.Name()]
+	if !ok {
+		// Measurements the collector does not build itself take the columns of
+		// their first point
+		s = newSchema(point)
+		w.schemas[point.Name()] = s
+	}
+
+	site := sanitize(siteOf(point))
+	path, err := w.path(point, site)
+	if err != nil {
+		w.reportError(fmt.Errorf("error when rendering layout, %s", err))
+		return
+	}
+
+	key := point.Name() + "\x00" + site
+	f, ok := w.files[key]
+	if ok && f.path != path {
+		w.closeFile(f)
+		ok = false
+	}
+	if !ok {
+		f, err = w.openFile(path, s)
+		if err != nil {
+			delete(w.files, key)
+			w.reportError(fmt.Errorf("error when opening %s, %s", path, err))
+			return
+		}
+		w.files[key] = f
+	}
+
+	err = f.write(point)
+	if err != nil {
+		w.reportError(fmt.Errorf("error when writing to %s, %s", f.path, err))
+	}
+
+	// Full row groups are written out rather than held until the file closes
+	if f.pq != nil && f.rows >= w.rowGroupSize {
+		err = f.pq.Flush()
+		f.rows = 0
+		if err != nil {
+			w.reportError(fmt.Errorf("error when flushing %s, %s", f.path, err))
+		}
+	}
+}
+
+// siteOf names the site a point belongs to
+func siteOf(point *write.Point) string {
+	var gateway string
+	for _, tag := range point.TagList() {
+		switch tag.Key {
+		case "site_name":
+			if tag.Value != "" {
+				return tag.Value
+			}
+		case "gateway_id":
+			gateway = tag.Value
+		}
+	}
+	if gateway != "" {
+		return gateway
+	}
+	return "unknown"
+}
+
+// sanitize keeps a layout value from escaping its directory
+func sanitize(s string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(s)
+}
+
+// path renders the layout for a point, without the file extension
+func (w *Writer) path(point *write.Point, site string) (string, error) {
+	t := point.Time().Local()
+	var buf bytes.Buffer
+	err := w.layout.Execute(&buf, layoutData{
+		Site:        site,
+		Measurement: sanitize(point.Name()),
+		Date:        t.Format("2006-01-02"),
+		Year:        t.Format("2006"),
+		Month:       t.Format("01"),
+		Day:         t.Format("02"),
+		Hour:        t.Format("15"),
+	})
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(w.directory, filepath.Clean("/"+buf.String())), nil
+}
+
+func (w *Writer) openFile(path string, s *schema) (*file, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &file{path: path, schema: s, opened: time.Now()}
+
+	if w.format == "parquet" {
+		// Parquet files cannot be appended to, so a file reopened within the
+		// same period gets a numbered name; it is written under a .partial
+		// suffix until its footer is complete
+		f.final = path + ".parquet"
+		for i := 1; ; i++ {
+			_, err = os.Stat(f.final)
+			if os.IsNotExist(err) {
+				break
+			}
+			f.final = fmt.Sprintf("%s-%d.parquet", path, i)
+		}
+		f.f, err = os.Create(f.final + ".partial")
+		if err != nil {
+			return nil, err
+		}
+		opts := []parquet.WriterOption{s.parquet}
+		if w.codec != nil {
+			opts = append(opts, parquet.Compression(w.codec))
+		}
+		f.pq = parquet.NewWriter(f.f, opts...)
+		return f, nil
+	}
+
+	name := path + ".csv"
+	if w.compression == "gzip" {
+		name += ".gz"
+	}
+	f.f, err = os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.f.Stat()
+	if err != nil {
+		f.f.Close()
+		return nil, err
+	}
+
+	// Each reopening of a gzip file appends a new gzip member, which readers
+	// decompress as one stream
+	if w.compression == "gzip" {
+		f.gz = gzip.NewWriter(f.f)
+		f.csv = csv.NewWriter(f.gz)
+	} else {
+		f.csv = csv.NewWriter(f.f)
+	}
+
+	if info.Size() == 0 {
+		header := []string{"time"}
+		for _, c := range s.columns {
+			header = append(header, c.name)
+		}
+		err = f.csv.Write(header)
+		if err != nil {
+			f.f.Close()
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (w *Writer) closeFile(f *file) {
+	var err error
+	if f.pq != nil {
+		err = f.pq.Close()
+	} else {
+		f.csv.Flush()
+		err = f.csv.Error()
+		if err == nil && f.gz != nil {
+			err = f.gz.Close()
+		}
+	}
+	closeErr := f.f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil && f.pq != nil {
+		err = os.Rename(f.final+".partial", f.final)
+	}
+	if err != nil {
+		w.reportError(fmt.Errorf("error when closing %s, %s", f.path, err))
+	}
+}
+
+func (f *file) write(point *write.Point) error {
+	values := f.schema.values(point)
+
+	if f.pq != nil {
+		row := make(parquet.Row, len(values)+1)
+		for i, v := range values {
+			leaf := f.schema.leaf[i]
+			if v == nil {
+				row[leaf] = parquet.NullValue().Level(0, 0, leaf)
+			} else {
+				row[leaf] = parquet.ValueOf(v).Level(0, 1, leaf)
+			}
+		}
+		leaf := f.schema.leaf[len(values)]
+		row[leaf] = parquet.Int64Value(point.Time().UnixMicro()).Level(0, 0, leaf)
+		_, err := f.pq.WriteRows([]parquet.Row{row})
+		if err == nil {
+			f.rows++
+		}
+		return err
+	}
+
+	record := make([]string, len(values)+1)
+	record[0] = point.Time().Format(time.RFC3339Nano)
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case float64:
+			record[i+1] = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			record[i+1] = fmt.Sprintf("%v", v)
+		}
+	}
+	return f.csv.Write(record)
+}
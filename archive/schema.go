@@ -0,0 +1,285 @@
+package archive
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/battery"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/demand"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/events"
+	"github.com/iwvelando/tesla-energy-stats-collector/influxdb"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/outage"
+	"github.com/iwvelando/tesla-energy-stats-collector/tariff"
+	"github.com/parquet-go/parquet-go"
+	"sort"
+	"time"
+)
+
+// Column kinds; tags are always strings
+const (
+	kindTag = iota
+	kindFloat
+	kindInt
+	kindUint
+	kindBool
+	kindString
+)
+
+type column struct {
+	name string
+	kind int
+}
+
+// schema is the fixed column layout of a measurement: time, then the tags and
+// then the fields, each sorted by name
+type schema struct {
+	columns []column
+	index   map[string]int
+	parquet *parquet.Schema
+	// leaf maps each column to its position in the parquet schema, with time
+	// at leaf[len(columns)]
+	leaf []int
+}
+
+// sample returns a snapshot with one of every repeated element so that the
+// points built from it cover every tag and field WriteAll can emit
+func sample() model.Teg {
+	var metrics model.Teg
+	metrics.Powerwalls.Sync.CommissioningDiagnostic.Checks = make([]model.TegPowerwallsCheck, 1)
+	metrics.Powerwalls.Sync.UpdateDiagnostic.Checks = make([]model.TegPowerwallsCheck, 1)
+	metrics.SystemStatus.BatteryBlocks = make([]model.TegBatteryBlock, 1)
+	metrics.NetworkConnectionTests.Checks = make([]model.TegNetworkConnectionCheck, 1)
+	metrics.SystemStatus.GridFaults = []model.TegGridFault{{
+		DecodedAlert: []model.TegGridAlert{{Value: ""}},
+	}}
+	return metrics
+}
+
+// derivedPoints builds the points of the measurements derived from the polls
+// (energy, cost, demand, outages, events and battery health) from samples
+// which set every optional field, so their columns are known before the
+// first of them is written
+func derivedPoints(conf *config.Configuration, metrics model.Teg) []*write.Point {
+	meters := map[string]energy.Flow{}
+	resets := map[string]string{}
+	for _, m := range energy.Meters {
+		meters[m] = energy.Flow{Imported: 1, Exported: 1}
+		resets[m] = energy.ResetCounter
+	}
+	flows := energy.Flows{SolarToLoad: 1, BatteryToLoad: 1, GridToLoad: 1}
+	totals := energy.Totals{
+		End:           metrics.Meters.Timestamp,
+		Interval:      meters,
+		Daily:         meters,
+		Monthly:       meters,
+		Lifetime:      meters,
+		IntervalFlows: flows,
+		DailyFlows:    flows,
+		MonthlyFlows:  flows,
+		LifetimeFlows: flows,
+		Resets:        resets,
+	}
+
+	// A time is needed wherever a zero time means the field is not set
+	ts := time.Unix(0, 0)
+	d := demand.Demand{
+		Time:        ts,
+		RollingFull: true,
+		PeakTime:    ts,
+		Closed:      []demand.Window{{Start: ts}},
+	}
+
+	var points []*write.Point
+	points = append(points, influxdb.EnergyPoints(conf, metrics, []energy.Totals{totals})...)
+	points = append(points, influxdb.CostPoints(conf, metrics, []tariff.Cost{{}})...)
+	points = append(points, influxdb.DemandPoints(conf, metrics, d)...)
+	points = append(points, influxdb.OutagePoints(conf, metrics, &outage.Event{End: ts})...)
+	points = append(points, influxdb.EventPoints(conf, metrics, []events.Event{{}})...)
+	points = append(points, influxdb.BatteryHealthPoints(conf, metrics, []battery.Health{{DegradationEstimated: true}})...)
+	return points
+}
+
+// pointCollector is a WriteAPI which keeps the points written to it
+type pointCollector struct {
+	points []*write.Point
+}
+
+func (c *pointCollector) WriteRecord(line string) {}
+
+func (c *pointCollector) WritePoint(point *write.Point) {
+	c.points = append(c.points, point)
+}
+
+func (c *pointCollector) Flush() {}
+
+func (c *pointCollector) Errors() <-chan error {
+	return nil
+}
+
+func (c *pointCollector) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {}
+
+// knownSchemas derives a schema per measurement from the points WriteAll and
+// the derived measurements build, merging the different point shapes sharing
+// a measurement; with aggregation the aggregated measurements take the
+// columns of their windows instead
+func knownSchemas(conf *config.Configuration) map[string]*schema {
+	metrics := sample()
+	points := append(influxdb.Points(conf, metrics), derivedPoints(conf, metrics)...)
+	if conf.Aggregation.Window > 0 {
+		c := &pointCollector{}
+		a := influxdb.NewAggregator(conf, c)
+		for _, p := range points {
+			a.WritePoint(p)
+		}
+		a.Flush()
+		points = c.points
+	}
+
+	byMeasurement := map[string][]*write.Point{}
+	for _, p := range points {
+		byMeasurement[p.Name()] = append(byMeasurement[p.Name()], p)
+	}
+
+	schemas := map[string]*schema{}
+	for measurement, points := range byMeasurement {
+		schemas[measurement] = newSchema(points...)
+	}
+	return schemas
+}
+
+func fieldKind(v interface{}) int {
+	switch v.(type) {
+	case float64:
+		return kindFloat
+	case int64:
+		return kindInt
+	case uint64:
+		return kindUint
+	case bool:
+		return kindBool
+	}
+	return kindString
+}
+
+func newSchema(points ...*write.Point) *schema {
+	tags := map[string]bool{}
+	fields := map[string]int{}
+	for _, p := range points {
+		for _, tag := range p.TagList() {
+			tags[tag.Key] = true
+		}
+		for _, field := range p.FieldList() {
+			fields[field.Key] = fieldKind(field.Value)
+		}
+	}
+
+	s := &schema{index: map[string]int{}}
+	var names []string
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.columns = append(s.columns, column{name: name, kind: kindTag})
+	}
+
+	names = nil
+	for name := range fields {
+		if !tags[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.columns = append(s.columns, column{name: name, kind: fields[name]})
+	}
+
+	group := parquet.Group{"time": parquet.Timestamp(parquet.Microsecond)}
+	for i, c := range s.columns {
+		s.index[c.name] = i
+		var node parquet.Node
+		switch c.kind {
+		case kindFloat:
+			node = parquet.Leaf(parquet.DoubleType)
+		case kindInt:
+			node = parquet.Int(64)
+		case kindUint:
+			node = parquet.Uint(64)
+		case kindBool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			node = parquet.String()
+		}
+		group[c.name] = parquet.Optional(node)
+	}
+	s.parquet = parquet.NewSchema("point", group)
+
+	leaves := map[string]int{}
+	for i, path := range s.parquet.Columns() {
+		leaves[path[0]] = i
+	}
+	s.leaf = make([]int, len(s.columns)+1)
+	for i, c := range s.columns {
+		s.leaf[i] = leaves[c.name]
+	}
+	s.leaf[len(s.columns)] = leaves["time"]
+
+	return s
+}
+
+// values lays out the tags and fields of a point in column order, converting
+// numbers to the column kind; missing or mismatched values are nil
+func (s *schema) values(p *write.Point) []interface{} {
+	values := make([]interface{}, len(s.columns))
+	for _, tag := range p.TagList() {
+		if i, ok := s.index[tag.Key]; ok {
+			values[i] = tag.Value
+		}
+	}
+	for _, field := range p.FieldList() {
+		i, ok := s.index[field.Key]
+		if !ok {
+			continue
+		}
+		values[i] = convert(s.columns[i].kind, field.Value)
+	}
+	return values
+}
+
+func convert(kind int, v interface{}) interface{} {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case int64:
+		f = float64(n)
+	case uint64:
+		f = float64(n)
+	default:
+		if fieldKind(v) == kind || (kind == kindTag && fieldKind(v) == kindString) {
+			return v
+		}
+		return nil
+	}
+
+	switch kind {
+	case kindFloat:
+		return f
+	case kindInt:
+		if n, ok := v.(int64); ok {
+			return n
+		}
+		return int64(f)
+	case kindUint:
+		if n, ok := v.(uint64); ok {
+			return n
+		}
+		if f < 0 {
+			return nil
+		}
+		return uint64(f)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+// Package battery tracks the usable capacity and throughput of each Powerwall
+// battery block to follow its health against the nameplate capacity.
+package battery
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultNameplateWh is the usable capacity of a Powerwall 2 or 3 block
+const defaultNameplateWh = 13500
+
+// maxHistory is how many days of capacity are kept to estimate degradation
+const maxHistory = 3 * 365
+
+// Capacity is a block's mean full pack energy over a day
+type Capacity struct {
+	Date     string  `json:"date"`
+	Capacity float64 `json:"capacity"`
+}
+
+// blockState accumulates a block's day and keeps its capacity history
+type blockState struct {
+	PartNumber    string     `json:"part_number"`
+	Date          string     `json:"date"`
+	Samples       int        `json:"samples"`
+	CapacitySum   float64    `json:"capacity_sum"`
+	CapacityMin   float64    `json:"capacity_min"`
+	CapacityMax   float64    `json:"capacity_max"`
+	Charged       float64    `json:"charged"`
+	Discharged    float64    `json:"discharged"`
+	DayCharged    float64    `json:"day_charged"`
+	DayDischarged float64    `json:"day_discharged"`
+	History       []Capacity `json:"history"`
+}
+
+// Health is a block's health over a day; energies are in Wh
+type Health struct {
+	Serial     string
+	PartNumber string
+	Date       string
+	Time       time.Time
+	Samples    int
+
+	NameplateWh   float64
+	CapacityWh    float64
+	CapacityMinWh float64
+	CapacityMaxWh float64
+	StateOfHealth float64
+
+	DayChargedWh         float64
+	DayDischargedWh      float64
+	ChargedWh            float64
+	DischargedWh         float64
+	EquivalentFullCycles float64
+
+	// DegradationPercentPerYear is the capacity lost per year as a percentage
+	// of nameplate, estimated once the history spans enough days
+	DegradationPercentPerYear float64
+	DegradationEstimated      bool
+}
+
+// Tracker follows each block by its serial number
+type Tracker struct {
+	path      string
+	timezone  string
+	nameplate float64
+	minDays   int
+
+	// mu guards the blocks, which Close saves while the poll loop may still
+	// be updating them
+	mu     sync.Mutex
+	closed bool
+	blocks map[string]*blockState
+}
+
+// New loads the tracker's state, if any was saved
+func New(conf *config.Configuration) (*Tracker, error) {
+	if conf.Battery.MinDays == 0 {
+		conf.Battery.MinDays = 30
+	}
+
+	t := &Tracker{
+		path:      conf.Battery.StateFile,
+		timezone:  conf.Energy.Timezone,
+		nameplate: conf.Battery.NameplateKwh * 1000,
+		minDays:   int(conf.Battery.MinDays),
+		blocks:    map[string]*blockState{},
+	}
+
+	_, err := energy.Location(t.timezone, model.Teg{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = energy.LoadState(t.path, &t.blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Close saves each block's day so far, counters and capacity history; blocks
+// polled afterwards are no longer tracked
+func (t *Tracker) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	return t.save()
+}
+
+func (t *Tracker) save() error {
+	return energy.SaveState(t.path, t.blocks)
+}
+
+// nameplate is the configured capacity, else the site's nominal energy shared
+// between its blocks, else a Powerwall's
+func (t *Tracker) nameplateWh(metrics model.Teg) float64 {
+	if t.nameplate > 0 {
+		return t.nameplate
+	}
+	blocks := len(metrics.SystemStatus.BatteryBlocks)
+	if metrics.SiteInfo.NominalSystemEnergyKwh > 0 && blocks > 0 {
+		return metrics.SiteInfo.NominalSystemEnergyKwh * 1000 / float64(blocks)
+	}
+	return defaultNameplateWh
+}
+
+// Update adds a snapshot to each block's day and returns the health of the
+// blocks whose day closed; the state is saved whenever a day closes
+func (t *Tracker) Update(metrics model.Teg) ([]Health, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, nil
+	}
+
+	loc, err := energy.Location(t.timezone, metrics)
+	if err != nil {
+		return nil, err
+	}
+	date := metrics.SystemStatus.Timestamp.In(loc).Format(energy.DateLayout)
+	nameplate := t.nameplateWh(metrics)
+
+	var health []Health
+	for _, block := range metrics.SystemStatus.BatteryBlocks {
+		if block.PackageSerialNumber == "" {
+			continue
+		}
+		b, ok := t.blocks[block.PackageSerialNumber]
+		if !ok {
+			b = &blockState{
+				Date:       date,
+				Charged:    float64(block.EnergyCharged),
+				Discharged: float64(block.EnergyDischarged),
+			}
+			t.blocks[block.PackageSerialNumber] = b
+		}
+		if b.Date != date {
+			if b.Samples > 0 {
+				h, err := t.close(block.PackageSerialNumber, b, nameplate, loc)
+				if err != nil {
+					return nil, err
+				}
+				health = append(health, h)
+			}
+			b.reset(date)
+		}
+		b.add(block)
+	}
+
+	if len(health) > 0 {
+		err = t.save()
+		if err != nil {
+			return health, err
+		}
+	}
+
+	return health, nil
+}
+
+// add accumulates a sample; a counter going backwards is rebaselined
+func (b *blockState) add(block model.TegBatteryBlock) {
+	b.PartNumber = block.PackagePartNumber
+
+	capacity := float64(block.NominalFullPackEnergy)
+	if capacity > 0 {
+		if b.Samples == 0 {
+			b.CapacityMin = capacity
+			b.CapacityMax = capacity
+		}
+		b.Samples++
+		b.CapacitySum += capacity
+		b.CapacityMin = math.Min(b.CapacityMin, capacity)
+		b.CapacityMax = math.Max(b.CapacityMax, capacity)
+	}
+
+	charged := float64(block.EnergyCharged)
+	if charged >= b.Charged {
+		b.DayCharged += charged - b.Charged
+	}
+	b.Charged = charged
+
+	discharged := float64(block.EnergyDischarged)
+	if discharged >= b.Discharged {
+		b.DayDischarged += discharged - b.Discharged
+	}
+	b.Discharged = discharged
+}
+
+// reset starts a new day
+func (b *blockState) reset(date string) {
+	b.Date = date
+	b.Samples = 0
+	b.CapacitySum = 0
+	b.CapacityMin = 0
+	b.CapacityMax = 0
+	b.DayCharged = 0
+	b.DayDischarged = 0
+}
+
+// close records the day's capacity in the history and reports its health
+func (t *Tracker) close(serial string, b *blockState, nameplate float64, loc *time.Location) (Health, error) {
+	day, err := time.ParseInLocation(energy.DateLayout, b.Date, loc)
+	if err != nil {
+		return Health{}, fmt.Errorf("error when parsing date %s, %s", b.Date, err)
+	}
+
+	capacity := b.CapacitySum / float64(b.Samples)
+	b.History = append(b.History, Capacity{Date: b.Date, Capacity: capacity})
+	if len(b.History) > maxHistory {
+		b.History = b.History[len(b.History)-maxHistory:]
+	}
+
+	h := Health{
+		Serial:               serial,
+		PartNumber:           b.PartNumber,
+		Date:                 b.Date,
+		Time:                 day.AddDate(0, 0, 1).Add(-time.Second),
+		Samples:              b.Samples,
+		NameplateWh:          nameplate,
+		CapacityWh:           capacity,
+		CapacityMinWh:        b.CapacityMin,
+		CapacityMaxWh:        b.CapacityMax,
+		StateOfHealth:        capacity / nameplate,
+		DayChargedWh:         b.DayCharged,
+		DayDischargedWh:      b.DayDischarged,
+		ChargedWh:            b.Charged,
+		DischargedWh:         b.Discharged,
+		EquivalentFullCycles: b.Discharged / nameplate,
+	}
+
+	slope, span, ok := trend(b.History, loc)
+	if ok && span >= t.minDays {
+		h.DegradationPercentPerYear = -slope * 365 / nameplate * 100
+		h.DegradationEstimated = true
+	}
+
+	return h, nil
+}
+
+// trend fits a line through the capacity history by least squares and
+// returns its slope in Wh per day and the days the history spans
+func trend(history []Capacity, loc *time.Location) (float64, int, bool) {
+	if len(history) < 2 {
+		return 0, 0, false
+	}
+	first, err := time.ParseInLocation(energy.DateLayout, history[0].Date, loc)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	var n, sumX, sumY, sumXX, sumXY, lastX float64
+	for _, c := range history {
+		day, err := time.ParseInLocation(energy.DateLayout, c.Date, loc)
+		if err != nil {
+			continue
+		}
+		x := math.Round(day.Sub(first).Hours() / 24)
+		n++
+		sumX += x
+		sumY += c.Capacity
+		sumXX += x * x
+		sumXY += x * c.Capacity
+		lastX = x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator, int(lastX), true
+}
@@ -0,0 +1,353 @@
+// Package billing accumulates the energy through the site meter into the
+// utility's billing cycles and settles them by its net metering rules, so the
+// true-up can be projected before it arrives.
+package billing
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/tariff"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Net metering rules
+const (
+	// RuleNEM2 credits exports at the retail import price and settles the
+	// energy charges once a year at the true-up
+	RuleNEM2 = "nem2"
+	// RuleNEM3 credits exports at the export price and settles the energy
+	// charges every cycle, carrying credits forward until the true-up
+	RuleNEM3 = "nem3"
+)
+
+// Statement is a billing cycle; End is the first day after the cycle and
+// amounts are in the tariff's currency
+type Statement struct {
+	Start         string  `json:"start"`
+	End           string  `json:"end"`
+	Hours         float64 `json:"hours"`
+	ImportedKWh   float64 `json:"imported_kwh"`
+	ExportedKWh   float64 `json:"exported_kwh"`
+	ImportCost    float64 `json:"import_cost"`
+	ExportCredit  float64 `json:"export_credit"`
+	FixedCharge   float64 `json:"fixed_charge"`
+	NonBypassable float64 `json:"non_bypassable"`
+
+	// Set when the cycle is settled
+	Deferred      float64 `json:"deferred"`
+	CreditApplied float64 `json:"credit_applied"`
+	CreditCarried float64 `json:"credit_carried"`
+	Due           float64 `json:"due"`
+}
+
+// EnergyCharge is the import cost less the export credit
+func (s Statement) EnergyCharge() float64 {
+	return s.ImportCost - s.ExportCredit
+}
+
+// scale extrapolates the cycle so far to its full length
+func (s Statement) scale(x float64) Statement {
+	s.Hours *= x
+	s.ImportedKWh *= x
+	s.ExportedKWh *= x
+	s.ImportCost *= x
+	s.ExportCredit *= x
+	s.FixedCharge *= x
+	s.NonBypassable *= x
+	return s
+}
+
+// Period is the year between true-ups; End is the day of the true-up
+type Period struct {
+	Start       string      `json:"start"`
+	End         string      `json:"end"`
+	Rule        string      `json:"rule"`
+	Statements  []Statement `json:"statements"`
+	ImportedKWh float64     `json:"imported_kwh"`
+	ExportedKWh float64     `json:"exported_kwh"`
+	// Deferred is the energy charges awaiting the true-up under NEM 2.0
+	Deferred float64 `json:"deferred"`
+	// Credit is the credit carried forward under NEM 3.0
+	Credit float64 `json:"credit"`
+
+	// Set at the true-up; a negative settlement is paid to the customer
+	Settled    bool    `json:"settled"`
+	Surplus    float64 `json:"surplus"`
+	Forfeited  float64 `json:"forfeited"`
+	Settlement float64 `json:"settlement"`
+}
+
+// State is what the Ledger persists and the statement command reads
+type State struct {
+	Currency string    `json:"currency"`
+	Cycle    Statement `json:"cycle"`
+	Period   Period    `json:"period"`
+	History  []Period  `json:"history"`
+}
+
+// Ledger accumulates energy totals into billing cycles
+type Ledger struct {
+	conf   config.Billing
+	tariff *tariff.Tariff
+
+	path         string
+	saveInterval time.Duration
+	saved        time.Time
+
+	// mu guards the open cycle and the closed periods, which Close saves
+	// while the poll loop may still be closing a cycle
+	mu     sync.Mutex
+	closed bool
+	state  State
+}
+
+// Check validates the billing configuration and sets its defaults
+func Check(conf *config.Configuration) error {
+	if conf.Billing.Rule == "" {
+		conf.Billing.Rule = RuleNEM2
+	}
+	if conf.Billing.Rule != RuleNEM2 && conf.Billing.Rule != RuleNEM3 {
+		return fmt.Errorf("unsupported rule %s, must be one of %s or %s", conf.Billing.Rule, RuleNEM2, RuleNEM3)
+	}
+	if conf.Billing.CycleDay == 0 {
+		conf.Billing.CycleDay = 1
+	}
+	if conf.Billing.CycleDay < 1 || conf.Billing.CycleDay > 28 {
+		return fmt.Errorf("cycleDay must be between 1 and 28")
+	}
+	for _, start := range conf.Billing.CycleStarts {
+		_, err := time.Parse(energy.DateLayout, start)
+		if err != nil {
+			return fmt.Errorf("cycle start %s must be given as YYYY-MM-DD", start)
+		}
+	}
+	sort.Strings(conf.Billing.CycleStarts)
+	if conf.Billing.TrueUp == "" {
+		conf.Billing.TrueUp = "01-01"
+	}
+	_, err := time.Parse("01-02", conf.Billing.TrueUp)
+	if err != nil {
+		return fmt.Errorf("trueUp %s must be given as MM-DD", conf.Billing.TrueUp)
+	}
+	return nil
+}
+
+// New validates the configuration and loads the ledger's state, if any was
+// saved
+func New(conf *config.Configuration) (*Ledger, error) {
+	err := Check(conf)
+	if err != nil {
+		return nil, err
+	}
+	t, err := tariff.Compile(conf)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Energy.SaveInterval == 0 {
+		conf.Energy.SaveInterval = time.Minute
+	}
+
+	l := &Ledger{
+		conf:         conf.Billing,
+		tariff:       t,
+		path:         conf.Billing.StateFile,
+		saveInterval: conf.Energy.SaveInterval,
+	}
+
+	l.state, err = Load(conf)
+	if err != nil {
+		return nil, err
+	}
+	l.state.Currency = t.Currency
+
+	return l, nil
+}
+
+// Load reads the saved state
+func Load(conf *config.Configuration) (State, error) {
+	var s State
+	err := energy.LoadState(conf.Billing.StateFile, &s)
+	return s, err
+}
+
+// Close saves the state; totals arriving afterwards are ignored
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.save()
+}
+
+func (l *Ledger) save() error {
+	return energy.SaveState(l.path, l.state)
+}
+
+// Cycle returns the billing cycle a date falls in: between the listed cycle
+// starts, else monthly from the cycle day
+func Cycle(conf config.Billing, date time.Time) (time.Time, time.Time) {
+	starts := conf.CycleStarts
+	day := date.Format(energy.DateLayout)
+	i := sort.SearchStrings(starts, day)
+	if i < len(starts) && starts[i] == day {
+		i++
+	}
+	if i > 0 && i < len(starts) {
+		start, _ := time.Parse(energy.DateLayout, starts[i-1])
+		end, _ := time.Parse(energy.DateLayout, starts[i])
+		return start, end
+	}
+
+	cycleDay := conf.CycleDay
+	if i > 0 {
+		last, _ := time.Parse(energy.DateLayout, starts[len(starts)-1])
+		cycleDay = last.Day()
+	}
+	start := time.Date(date.Year(), date.Month(), cycleDay, 0, 0, 0, 0, time.UTC)
+	if date.Day() < cycleDay {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start, start.AddDate(0, 1, 0)
+}
+
+// period returns the true-up period a date falls in
+func period(conf config.Billing, date time.Time) (time.Time, time.Time) {
+	trueUp, _ := time.Parse("01-02", conf.TrueUp)
+	start := time.Date(date.Year(), trueUp.Month(), trueUp.Day(), 0, 0, 0, 0, time.UTC)
+	if start.After(date) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return start, start.AddDate(1, 0, 0)
+}
+
+// open starts the cycle, and if need be the true-up period, of a date
+func (l *Ledger) open(date time.Time) {
+	if l.state.Period.Start == "" {
+		start, end := period(l.conf, date)
+		l.state.Period = Period{
+			Start: start.Format(energy.DateLayout),
+			End:   end.Format(energy.DateLayout),
+			Rule:  l.conf.Rule,
+		}
+	}
+	start, end := Cycle(l.conf, date)
+	if start.Format(energy.DateLayout) < l.state.Period.Start {
+		start, _ = time.Parse(energy.DateLayout, l.state.Period.Start)
+	}
+	if end.Format(energy.DateLayout) > l.state.Period.End {
+		end, _ = time.Parse(energy.DateLayout, l.state.Period.End)
+	}
+	l.state.Cycle = Statement{
+		Start: start.Format(energy.DateLayout),
+		End:   end.Format(energy.DateLayout),
+	}
+}
+
+// Update adds energy totals to the billing cycle, settling the cycle and
+// the true-up period when a day past them arrives
+func (l *Ledger) Update(totals []energy.Totals) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+
+	for _, t := range totals {
+		date, err := time.Parse(energy.DateLayout, t.Date)
+		if err != nil {
+			return fmt.Errorf("error when parsing date %s, %s", t.Date, err)
+		}
+
+		if l.state.Cycle.Start != "" && t.Date >= l.state.Cycle.End {
+			settleCycle(&l.state.Period, l.state.Cycle)
+			l.state.Cycle = Statement{}
+			if t.Date >= l.state.Period.End {
+				settlePeriod(&l.state.Period, l.conf.SurplusRate)
+				l.state.History = append(l.state.History, l.state.Period)
+				l.state.Period = Period{}
+			}
+		}
+		if l.state.Cycle.Start == "" {
+			l.open(date)
+		}
+
+		amounts, _, err := l.tariff.Price(t)
+		if err != nil {
+			return err
+		}
+		imported := t.Interval[energy.Site].Imported / 1000
+		c := &l.state.Cycle
+		c.Hours += t.End.Sub(t.Start).Hours()
+		c.ImportedKWh += imported
+		c.ExportedKWh += t.Interval[energy.Site].Exported / 1000
+		c.ImportCost += amounts.ImportCost
+		if l.state.Period.Rule == RuleNEM2 {
+			c.ExportCredit += amounts.ExportRetail
+		} else {
+			c.ExportCredit += amounts.ExportCredit
+		}
+		c.FixedCharge += amounts.FixedCharge
+		c.NonBypassable += imported * l.conf.NonBypassable
+	}
+
+	if time.Since(l.saved) >= l.saveInterval {
+		err := l.save()
+		if err != nil {
+			return err
+		}
+		l.saved = time.Now()
+	}
+
+	return nil
+}
+
+// settleCycle closes a cycle into its period: the fixed and non-bypassable
+// charges are always due, while the energy charge is deferred to the
+// true-up under NEM 2.0 or offset by the carried credit under NEM 3.0
+func settleCycle(p *Period, s Statement) {
+	energyCharge := s.EnergyCharge()
+	s.Due = s.FixedCharge + s.NonBypassable
+
+	switch p.Rule {
+	case RuleNEM2:
+		s.Deferred = energyCharge
+		p.Deferred += energyCharge
+	default:
+		if energyCharge < 0 {
+			p.Credit -= energyCharge
+		} else {
+			s.CreditApplied = math.Min(p.Credit, energyCharge)
+			p.Credit -= s.CreditApplied
+			s.Due += energyCharge - s.CreditApplied
+		}
+		s.CreditCarried = p.Credit
+	}
+
+	p.ImportedKWh += s.ImportedKWh
+	p.ExportedKWh += s.ExportedKWh
+	p.Statements = append(p.Statements, s)
+}
+
+// settlePeriod trues up a period: deferred charges are due, credits left
+// over are forfeited, and net surplus energy is paid at the surplus rate
+func settlePeriod(p *Period, surplusRate float64) {
+	p.Surplus = math.Max(p.ExportedKWh-p.ImportedKWh, 0) * surplusRate
+	switch p.Rule {
+	case RuleNEM2:
+		if p.Deferred > 0 {
+			p.Settlement = p.Deferred
+		} else {
+			p.Forfeited = -p.Deferred
+		}
+	default:
+		p.Forfeited = p.Credit
+	}
+	p.Settlement -= p.Surplus
+	p.Settled = true
+}
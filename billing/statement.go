@@ -0,0 +1,169 @@
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Projection extrapolates the cycle and true-up period in progress
+type Projection struct {
+	// Cycle is the cycle in progress extrapolated to its end and settled
+	Cycle Statement `json:"cycle"`
+	// Period is the period settled as if the rest of it went as it did a
+	// year earlier, or else as it has so far
+	Period Period `json:"period"`
+}
+
+// days is the number of days between two dates
+func days(start, end string) float64 {
+	s, err := time.Parse(energy.DateLayout, start)
+	if err != nil {
+		return 0
+	}
+	e, err := time.Parse(energy.DateLayout, end)
+	if err != nil {
+		return 0
+	}
+	return e.Sub(s).Hours() / 24
+}
+
+// Project extrapolates the cycle in progress to its end in proportion to the
+// hours accounted in it, then the rest of the period from the same cycles a
+// year earlier if there is a history, else from the cycles so far
+func Project(s State, surplusRate float64) Projection {
+	var proj Projection
+
+	cycle := s.Cycle
+	if cycle.Hours > 0 {
+		cycle = cycle.scale(days(cycle.Start, cycle.End) * 24 / cycle.Hours)
+	}
+	period := s.Period
+	period.Statements = append([]Statement(nil), s.Period.Statements...)
+	settleCycle(&period, cycle)
+	proj.Cycle = period.Statements[len(period.Statements)-1]
+
+	// The rest of the period is expected to go as it did a year earlier, or
+	// else at the rate of the period so far
+	remaining := days(cycle.End, period.End) * 24
+	basis := period.Statements
+	fromHistory := false
+	if len(s.History) > 0 {
+		yearAgo, err := time.Parse(energy.DateLayout, cycle.End)
+		if err == nil {
+			from := yearAgo.AddDate(-1, 0, 0).Format(energy.DateLayout)
+			var previous []Statement
+			for _, st := range s.History[len(s.History)-1].Statements {
+				if st.Start >= from {
+					previous = append(previous, st)
+				}
+			}
+			if len(previous) > 0 {
+				basis = previous
+				fromHistory = true
+			}
+		}
+	}
+
+	var hours float64
+	for _, st := range basis {
+		hours += st.Hours
+	}
+	if hours > 0 && remaining > 0 {
+		if !fromHistory {
+			// Without a history the rest is a single cycle at the rate so far
+			rest := Statement{Start: cycle.End, End: period.End, Hours: hours}
+			for _, st := range basis {
+				rest.ImportedKWh += st.ImportedKWh
+				rest.ExportedKWh += st.ExportedKWh
+				rest.ImportCost += st.ImportCost
+				rest.ExportCredit += st.ExportCredit
+				rest.FixedCharge += st.FixedCharge
+				rest.NonBypassable += st.NonBypassable
+			}
+			basis = []Statement{rest}
+		}
+		for _, st := range basis {
+			settleCycle(&period, Statement{
+				Start:         st.Start,
+				End:           st.End,
+				Hours:         st.Hours,
+				ImportedKWh:   st.ImportedKWh,
+				ExportedKWh:   st.ExportedKWh,
+				ImportCost:    st.ImportCost,
+				ExportCredit:  st.ExportCredit,
+				FixedCharge:   st.FixedCharge,
+				NonBypassable: st.NonBypassable,
+			}.scale(remaining/hours))
+		}
+	}
+
+	settlePeriod(&period, surplusRate)
+	proj.Period = period
+	return proj
+}
+
+// Print writes the statements of the true-up period in progress, or of
+// every period if all is set, and the projection as a table or JSON
+func Print(w io.Writer, s State, proj Projection, all bool, format string) error {
+	periods := []Period{s.Period}
+	if all {
+		periods = append(append([]Period(nil), s.History...), s.Period)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"currency":   s.Currency,
+			"periods":    periods,
+			"cycle":      s.Cycle,
+			"projection": proj,
+		})
+	}
+	if format != "table" {
+		return fmt.Errorf("unsupported format %s, must be one of table or json", format)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "cycle\timported kWh\texported kWh\timport cost\texport credit\tfixed\tnon-bypassable\tenergy charge\tdeferred\tcredit\tdue\t\n")
+	row := func(label string, st Statement) {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			label, st.ImportedKWh, st.ExportedKWh, st.ImportCost, st.ExportCredit, st.FixedCharge,
+			st.NonBypassable, st.EnergyCharge(), st.Deferred, st.CreditCarried, st.Due)
+	}
+	for _, p := range periods {
+		for _, st := range p.Statements {
+			row(st.Start+" - "+st.End, st)
+		}
+		if p.Settled {
+			fmt.Fprintf(tw, "true-up %s (%s)\t\t\t\t\t\t\t\t\t\t%.2f\t\n", p.End, p.Rule, p.Settlement)
+		}
+	}
+	if s.Cycle.Start != "" {
+		row(s.Cycle.Start+" - "+s.Cycle.End+" so far", s.Cycle)
+		row(s.Cycle.Start+" - "+s.Cycle.End+" projected", proj.Cycle)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	p := proj.Period
+	if p.Start == "" {
+		return nil
+	}
+	fmt.Fprintf(w, "\nprojected true-up on %s (%s, %s):\n", p.End, p.Rule, s.Currency)
+	fmt.Fprintf(w, "  imported %.1f kWh, exported %.1f kWh\n", p.ImportedKWh, p.ExportedKWh)
+	if p.Rule == RuleNEM2 {
+		fmt.Fprintf(w, "  deferred energy charges %.2f\n", p.Deferred)
+	} else {
+		fmt.Fprintf(w, "  credit carried %.2f\n", p.Credit)
+	}
+	fmt.Fprintf(w, "  forfeited credit %.2f, net surplus compensation %.2f\n", p.Forfeited, p.Surplus)
+	fmt.Fprintf(w, "  settlement %.2f\n", p.Settlement)
+	return nil
+}
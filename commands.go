@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/billing"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/outage"
+	"github.com/iwvelando/tesla-energy-stats-collector/sqlite"
+	"os"
+	"time"
+)
+
+// parseTime accepts either a duration before now or an RFC3339 time
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// runQuery prints the selected fields from the SQLite store
+func runQuery(conf *config.Configuration, args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	start := flags.String("start", "1h", "Start of the range as a duration before now or an RFC3339 time")
+	end := flags.String("end", "0s", "End of the range as a duration before now or an RFC3339 time")
+	every := flags.Duration("every", 0, "Rollup resolution to read, for example 1m; 0 reads the raw samples")
+	stat := flags.String("stat", "mean", "Rollup statistic, one of mean, min, max or count")
+	format := flags.String("format", "table", "Output format, one of table, csv or json")
+	flags.Parse(args)
+
+	now := time.Now()
+	startTime, err := parseTime(*start, now)
+	if err != nil {
+		return err
+	}
+	endTime, err := parseTime(*end, now)
+	if err != nil {
+		return err
+	}
+
+	result, err := sqlite.Query(conf, sqlite.QueryOptions{
+		Fields: flags.Args(),
+		Start:  startTime,
+		End:    endTime,
+		Every:  *every,
+		Stat:   *stat,
+	})
+	if err != nil {
+		return err
+	}
+
+	return result.Print(os.Stdout, *format)
+}
+
+// runStatement prints the billing statements and the true-up projection
+func runStatement(conf *config.Configuration, args []string) error {
+	flags := flag.NewFlagSet("statement", flag.ExitOnError)
+	all := flags.Bool("all", false, "Also print the statements of past true-up periods")
+	format := flags.String("format", "table", "Output format, one of table or json")
+	flags.Parse(args)
+
+	if conf.Billing.StateFile == "" {
+		return fmt.Errorf("billing.stateFile is not configured")
+	}
+	err := billing.Check(conf)
+	if err != nil {
+		return err
+	}
+
+	state, err := billing.Load(conf)
+	if err != nil {
+		return err
+	}
+
+	return billing.Print(os.Stdout, state, billing.Project(state, conf.Billing.SurplusRate), *all, *format)
+}
+
+// runOutages prints the outage history
+func runOutages(conf *config.Configuration, args []string) error {
+	flags := flag.NewFlagSet("outages", flag.ExitOnError)
+	since := flags.String("since", "", "Only print outages since a duration before now or an RFC3339 time")
+	format := flags.String("format", "table", "Output format, one of table or json")
+	flags.Parse(args)
+
+	if conf.Outages.StateFile == "" {
+		return fmt.Errorf("outages.stateFile is not configured")
+	}
+
+	state, err := outage.Load(conf)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	events := state.Events()
+	if *since != "" {
+		sinceTime, err := parseTime(*since, now)
+		if err != nil {
+			return err
+		}
+		var recent []outage.Event
+		for _, e := range events {
+			if e.Ongoing() || e.End.After(sinceTime) {
+				recent = append(recent, e)
+			}
+		}
+		events = recent
+	}
+
+	return outage.Print(os.Stdout, events, now, *format)
+}
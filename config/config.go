@@ -12,6 +12,25 @@ import (
 type Configuration struct {
 	TeslaGateway TeslaGateway
 	InfluxDB     InfluxDB
+	Tags         Tags
+	ChangeFilter ChangeFilter
+	Aggregation  Aggregation
+	Energy       Energy
+	Battery      Battery
+	Tariff       Tariff
+	Billing      Billing
+	Demand       Demand
+	Outages      Outages
+	Events       Events
+	Alerting     Alerting
+	PostgreSQL   PostgreSQL
+	SQLite       SQLite
+	Archive      Archive
+	OTLP         OTLP
+	Stream       Stream
+	Webhooks     []Webhook
+	PVOutput     PVOutput
+	Modbus       Modbus
 	Polling      Polling
 }
 
@@ -25,6 +44,7 @@ type TeslaGateway struct {
 
 // InfluxDB holds the connection parameters for InfluxDB
 type InfluxDB struct {
+	Version           int
 	Address           string
 	Username          string
 	Password          string
@@ -36,6 +56,295 @@ type InfluxDB struct {
 	Bucket            string
 	SkipVerifySsl     bool
 	FlushInterval     uint
+	BatchSize         uint
+	Precision         string
+	Gzip              bool
+	Schema            string
+	Timezone          string
+	Routes            []InfluxDBRoute
+	Bootstrap         InfluxDBBootstrap
+}
+
+// InfluxDBRoute sends a set of measurements to a bucket or database/retention
+// policy other than the default one
+type InfluxDBRoute struct {
+	Measurements    []string
+	Bucket          string
+	Database        string
+	RetentionPolicy string
+	Retention       time.Duration
+}
+
+// InfluxDBBootstrap holds parameters for creating the InfluxDB destinations and
+// downsampling
+type InfluxDBBootstrap struct {
+	OnStartup  bool
+	Retention  time.Duration
+	Downsample []InfluxDBDownsample
+}
+
+// InfluxDBDownsample describes a rollup of the high frequency measurements into
+// means over a fixed interval
+type InfluxDBDownsample struct {
+	Every           time.Duration
+	Bucket          string
+	RetentionPolicy string
+	Retention       time.Duration
+}
+
+// Tags controls which metadata is written as tags and adds static tags
+type Tags struct {
+	AsFields []string
+	Drop     []string
+	Static   map[string]string
+}
+
+// ChangeFilter holds the parameters for writing fields only when they change
+type ChangeFilter struct {
+	Heartbeat    time.Duration
+	Measurements []string
+	Absolute     float64
+	Percent      float64
+	Deadbands    []ChangeFilterDeadband
+}
+
+// ChangeFilterDeadband overrides the deadband for the listed fields
+type ChangeFilterDeadband struct {
+	Fields   []string
+	Absolute float64
+	Percent  float64
+}
+
+// Aggregation holds the parameters for aggregating samples over windows
+// before they are written
+type Aggregation struct {
+	Window       time.Duration
+	Measurements []string
+	LastFields   []string
+}
+
+// Energy holds the parameters for accounting energy from the meter counters
+type Energy struct {
+	StateFile    string
+	Timezone     string
+	MaxPowerKw   float64
+	SaveInterval time.Duration
+	KPIs         bool
+	Flows        bool
+}
+
+// Battery holds the parameters for tracking the health of the battery blocks
+type Battery struct {
+	StateFile    string
+	NameplateKwh float64
+	MinDays      uint
+}
+
+// Tariff holds the utility tariff energy is priced at; prices are per kWh
+type Tariff struct {
+	StateFile   string
+	Currency    string
+	DailyCharge float64
+	Import      float64
+	Export      float64
+	Holidays    []string
+	Seasons     []TariffSeason
+}
+
+// TariffSeason holds the time of use periods of the months of a season
+type TariffSeason struct {
+	Name    string
+	Months  []int
+	Periods []TariffPeriod
+}
+
+// TariffPeriod holds the prices of a time of use period
+type TariffPeriod struct {
+	Name   string
+	Days   []string
+	Start  string
+	End    string
+	Import float64
+	Export float64
+}
+
+// Billing holds the billing cycles and net metering rules of the utility
+type Billing struct {
+	StateFile     string
+	Rule          string
+	CycleDay      int
+	CycleStarts   []string
+	TrueUp        string
+	NonBypassable float64
+	SurplusRate   float64
+}
+
+// Demand holds the parameters for tracking peak demand from the grid
+type Demand struct {
+	Window    time.Duration
+	Sliding   bool
+	Price     float64
+	StateFile string
+}
+
+// Outages holds the parameters for recording grid outages
+type Outages struct {
+	StateFile string
+}
+
+// Events holds the parameters for logging changes of settings and identity
+type Events struct {
+	StateFile string
+}
+
+// Alerting holds the alert rules and the channels their notifications are
+// sent to
+type Alerting struct {
+	StateFile string
+	Rules     []AlertRule
+	Notifiers []Notifier
+}
+
+// AlertRule holds a condition evaluated against each snapshot and how long it
+// must hold before the alert fires or resolves
+type AlertRule struct {
+	Name         string
+	Type         string
+	Severity     string
+	Threshold    float64
+	GridDown     bool
+	After        time.Duration
+	Start        string
+	End          string
+	Condition    string
+	For          time.Duration
+	ClearFor     time.Duration
+	Repeat       time.Duration
+	SkipResolved bool
+	Notifiers    []string
+}
+
+// Notifier holds the parameters for delivering alert notifications
+type Notifier struct {
+	Name          string
+	Type          string
+	URL           string
+	Token         string
+	User          string
+	Priority      int
+	Host          string
+	Username      string
+	Password      string
+	From          string
+	To            []string
+	Retries       uint
+	Timeout       time.Duration
+	SkipVerifySsl bool
+}
+
+// PostgreSQL holds the connection parameters for the PostgreSQL output
+type PostgreSQL struct {
+	ConnectionString string
+	BatchSize        uint
+}
+
+// SQLite holds the parameters for the embedded SQLite store
+type SQLite struct {
+	Path          string
+	Measurements  []string
+	Retention     time.Duration
+	Rollups       []SQLiteRollup
+	FlushInterval uint
+}
+
+// SQLiteRollup describes a table of mean, min and max values over a fixed
+// interval kept by the SQLite store
+type SQLiteRollup struct {
+	Every     time.Duration
+	Retention time.Duration
+}
+
+// Archive holds the parameters for writing points to CSV or Parquet files
+type Archive struct {
+	Directory      string
+	Format         string
+	Compression    string
+	Layout         string
+	Measurements   []string
+	FlushInterval  uint
+	RotateInterval time.Duration
+	RowGroupSize   int
+}
+
+// OTLP holds the parameters for the OpenTelemetry metrics exporter
+type OTLP struct {
+	Endpoint      string
+	Protocol      string
+	Insecure      bool
+	SkipVerifySsl bool
+	Headers       map[string]string
+	Timeout       time.Duration
+	Site          string
+}
+
+// Stream holds the parameters for publishing full snapshots to Kafka or NATS
+type Stream struct {
+	Format       string
+	BatchSize    uint
+	BatchTimeout time.Duration
+	BufferSize   uint
+	DropOnFull   bool
+	Kafka        StreamKafka
+	NATS         StreamNATS
+}
+
+// StreamKafka holds the Kafka connection parameters
+type StreamKafka struct {
+	Brokers       []string
+	Topic         string
+	Username      string
+	Password      string
+	TLS           bool
+	SkipVerifySsl bool
+}
+
+// StreamNATS holds the NATS connection parameters
+type StreamNATS struct {
+	URL         string
+	Subject     string
+	Token       string
+	Credentials string
+}
+
+// Webhook holds the parameters for posting snapshots to an HTTP endpoint
+type Webhook struct {
+	URL             string
+	Method          string
+	Template        string
+	Headers         map[string]string
+	Secret          string
+	SignatureHeader string
+	Retries         uint
+	MinInterval     time.Duration
+	Timeout         time.Duration
+	SkipVerifySsl   bool
+}
+
+// PVOutput holds the parameters for uploading status to PVOutput.org
+type PVOutput struct {
+	APIKey    string
+	SystemID  string
+	URL       string
+	Interval  time.Duration
+	BatchSize uint
+	Timeout   time.Duration
+}
+
+// Modbus holds the parameters for the Modbus TCP server
+type Modbus struct {
+	Listen      string
+	UnitID      uint8
+	BaseAddress uint16
 }
 
 // Polling holds parameters related to how we poll the Tesla Gateway
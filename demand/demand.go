@@ -0,0 +1,282 @@
+// Package demand tracks the average grid import over demand windows and the
+// peak of each billing period, which is what demand charges are billed on.
+package demand
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/billing"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+	"sync"
+	"time"
+)
+
+// segment is the energy imported between the previous poll and End in Wh
+type segment struct {
+	End    time.Time `json:"end"`
+	Energy float64   `json:"energy"`
+}
+
+// state is what the Tracker persists across restarts
+type state struct {
+	Period       string    `json:"period"`
+	Peak         float64   `json:"peak"`
+	PeakTime     time.Time `json:"peak_time"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnergy float64   `json:"window_energy"`
+	Since        time.Time `json:"since"`
+	Last         time.Time `json:"last"`
+	LastPower    float64   `json:"last_power"`
+	Segments     []segment `json:"segments"`
+}
+
+// Window is the average import over a demand window in W
+type Window struct {
+	Start  time.Time
+	End    time.Time
+	Demand float64
+	// Partial is set on a window which was not observed from its start, so
+	// it does not count towards the peak
+	Partial bool
+}
+
+// Demand is the state of demand at a poll; demands are in W
+type Demand struct {
+	Time        time.Time
+	Period      string
+	WindowStart time.Time
+	WindowEnd   time.Time
+	// Window is the average import of the window so far
+	Window float64
+	// Projected is the average the window ends at if the import stays at
+	// its current power
+	Projected float64
+	// Rolling is the average import over the trailing window, once a full
+	// window has been seen
+	Rolling     float64
+	RollingFull bool
+	// Peak is the highest demand of the billing period so far
+	Peak     float64
+	PeakTime time.Time
+	// Headroom is the average power the rest of the window can import
+	// without setting a new peak; it is negative once the window will, and
+	// zero while there is no peak
+	Headroom float64
+	// Closed lists the windows which ended since the previous poll
+	Closed []Window
+}
+
+// Tracker integrates the site import into demand windows
+type Tracker struct {
+	window       time.Duration
+	sliding      bool
+	billing      config.Billing
+	timezone     string
+	path         string
+	saveInterval time.Duration
+	saved        time.Time
+
+	// mu guards the window being integrated and the period's peak, which
+	// Close saves while the poll loop may still be adding an interval
+	mu     sync.Mutex
+	closed bool
+	state  state
+}
+
+// New validates the configuration and loads the tracker's state, if a state
+// file is configured and was saved
+func New(conf *config.Configuration) (*Tracker, error) {
+	if conf.Demand.Window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+	err := billing.Check(conf)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Energy.SaveInterval == 0 {
+		conf.Energy.SaveInterval = time.Minute
+	}
+
+	t := &Tracker{
+		window:       conf.Demand.Window,
+		sliding:      conf.Demand.Sliding,
+		billing:      conf.Billing,
+		timezone:     conf.Energy.Timezone,
+		path:         conf.Demand.StateFile,
+		saveInterval: conf.Energy.SaveInterval,
+	}
+
+	_, err = energy.Location(t.timezone, model.Teg{})
+	if err != nil {
+		return nil, err
+	}
+
+	if t.path != "" {
+		err = energy.LoadState(t.path, &t.state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Close saves the state, if a state file is configured; snapshots arriving
+// afterwards are ignored
+func (t *Tracker) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	return t.save()
+}
+
+func (t *Tracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+	return energy.SaveState(t.path, t.state)
+}
+
+// period returns the start of the billing cycle a time falls in
+func (t *Tracker) period(at time.Time, loc *time.Location) string {
+	y, m, d := at.In(loc).Date()
+	start, _ := billing.Cycle(t.billing, time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	return start.Format(energy.DateLayout)
+}
+
+// windowStart returns the start of the window a time falls in; windows are
+// counted from local midnight so they line up with the site's clock whatever
+// its offset from UTC
+func (t *Tracker) windowStart(at time.Time, loc *time.Location) time.Time {
+	y, m, d := at.In(loc).Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return midnight.Add(at.Sub(midnight).Truncate(t.window))
+}
+
+// consider sets a new peak if demand exceeds the peak of the billing period
+// the time falls in, starting the period afresh if need be
+func (t *Tracker) consider(demand float64, at time.Time, loc *time.Location) {
+	s := &t.state
+	if period := t.period(at, loc); period != s.Period {
+		s.Period = period
+		s.Peak = 0
+		s.PeakTime = time.Time{}
+	}
+	if demand > s.Peak {
+		s.Peak = demand
+		s.PeakTime = at
+	}
+}
+
+// Update integrates the import since the previous poll, at the power of the
+// previous poll, into the demand windows. Windows are aligned to the site's
+// clock and set the peak when they end; with sliding set the trailing window sets
+// it at every poll instead. A gap longer than a window is not integrated.
+func (t *Tracker) Update(metrics model.Teg) (Demand, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return Demand{}, nil
+	}
+
+	ts := metrics.Meters.Timestamp
+	loc, err := energy.Location(t.timezone, metrics)
+	if err != nil {
+		return Demand{}, err
+	}
+	power := math.Max(metrics.Meters.Site.InstantPowerWatts, 0)
+	hours := t.window.Hours()
+	s := &t.state
+
+	var closed []Window
+	switch {
+	case !s.Last.IsZero() && !ts.After(s.Last):
+		return Demand{}, nil
+	case s.Last.IsZero() || ts.Sub(s.Last) > t.window:
+		s.WindowStart = t.windowStart(ts, loc)
+		s.WindowEnergy = 0
+		s.Since = ts
+		s.Segments = nil
+	default:
+		for from := s.Last; from.Before(ts); {
+			windowEnd := s.WindowStart.Add(t.window)
+			to := ts
+			if windowEnd.Before(ts) {
+				to = windowEnd
+			}
+			wh := s.LastPower * to.Sub(from).Hours()
+			s.WindowEnergy += wh
+			s.Segments = append(s.Segments, segment{End: to, Energy: wh})
+			if !to.Before(windowEnd) {
+				w := Window{
+					Start:   s.WindowStart,
+					End:     windowEnd,
+					Demand:  s.WindowEnergy / hours,
+					Partial: s.WindowStart.Before(s.Since),
+				}
+				closed = append(closed, w)
+				if !t.sliding && !w.Partial {
+					t.consider(w.Demand, w.Start, loc)
+				}
+				s.WindowStart = windowEnd
+				s.WindowEnergy = 0
+			}
+			from = to
+		}
+	}
+	s.Last = ts
+	s.LastPower = power
+
+	// The trailing window
+	cutoff := ts.Add(-t.window)
+	for len(s.Segments) > 0 && !s.Segments[0].End.After(cutoff) {
+		s.Segments = s.Segments[1:]
+	}
+	var rolling float64
+	for _, seg := range s.Segments {
+		rolling += seg.Energy
+	}
+	rolling /= hours
+	rollingFull := !s.Since.After(cutoff)
+	if t.sliding && rollingFull {
+		t.consider(rolling, ts, loc)
+	}
+	// A new billing period starts without a peak
+	t.consider(0, ts, loc)
+
+	d := Demand{
+		Time:        ts,
+		Period:      s.Period,
+		WindowStart: s.WindowStart,
+		WindowEnd:   s.WindowStart.Add(t.window),
+		Rolling:     rolling,
+		RollingFull: rollingFull,
+		Peak:        s.Peak,
+		PeakTime:    s.PeakTime,
+		Closed:      closed,
+	}
+	elapsed := ts.Sub(s.WindowStart).Hours()
+	remaining := hours - elapsed
+	if elapsed > 0 {
+		d.Window = s.WindowEnergy / elapsed
+	}
+	d.Projected = (s.WindowEnergy + power*remaining) / hours
+	if remaining > 0 && s.Peak > 0 {
+		d.Headroom = (s.Peak*hours - s.WindowEnergy) / remaining
+	}
+
+	if t.path != "" && time.Since(t.saved) >= t.saveInterval {
+		err = t.save()
+		if err != nil {
+			return d, err
+		}
+		t.saved = time.Now()
+	}
+
+	return d, nil
+}
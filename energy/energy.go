@@ -0,0 +1,408 @@
+// Package energy accounts the energy through each meter from the gateway's
+// cumulative counters into interval, daily, monthly and lifetime totals.
+package energy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Meters accounted, in the order they are written
+const (
+	Site    = "site"
+	Battery = "battery"
+	Load    = "load"
+	Solar   = "solar"
+)
+
+// Meters lists the meters accounted
+var Meters = []string{Site, Battery, Load, Solar}
+
+// Reasons a meter's counters were rebaselined instead of accounted
+const (
+	ResetCounter     = "counter_reset"
+	ResetGateway     = "gateway_replaced"
+	ResetImplausible = "implausible_jump"
+)
+
+// Date layouts of the days and months totals are kept for
+const (
+	DateLayout  = "2006-01-02"
+	MonthLayout = "2006-01"
+)
+
+// Flow is the energy imported into and exported from a meter in Wh
+type Flow struct {
+	Imported float64 `json:"imported"`
+	Exported float64 `json:"exported"`
+}
+
+func (f Flow) add(g Flow) Flow {
+	return Flow{Imported: f.Imported + g.Imported, Exported: f.Exported + g.Exported}
+}
+
+func (f Flow) scale(x float64) Flow {
+	return Flow{Imported: f.Imported * x, Exported: f.Exported * x}
+}
+
+// Totals is the energy accounted for an interval along with the running
+// totals of the day and month it ends in and the lifetime totals
+type Totals struct {
+	Start    time.Time
+	End      time.Time
+	Date     string
+	Month    string
+	Location *time.Location
+	Interval map[string]Flow
+	Daily    map[string]Flow
+	Monthly  map[string]Flow
+	Lifetime map[string]Flow
+	// Flows attribute the interval, day, month and lifetime energy
+	IntervalFlows Flows
+	DailyFlows    Flows
+	MonthlyFlows  Flows
+	LifetimeFlows Flows
+	Resets        map[string]string
+	// Final is set on the totals which close a day
+	Final bool
+}
+
+// Time is when the totals are written; a day's final totals are written in
+// the last second of the day so they are grouped with it
+func (t Totals) Time() time.Time {
+	if t.Final {
+		return t.End.Add(-time.Second)
+	}
+	return t.End
+}
+
+// state is what the Accountant persists across restarts
+type state struct {
+	GatewayID string          `json:"gateway_id"`
+	Time      time.Time       `json:"time"`
+	Counters  map[string]Flow `json:"counters"`
+	Date      string          `json:"date"`
+	Month     string          `json:"month"`
+	Daily     map[string]Flow `json:"daily"`
+	Monthly   map[string]Flow `json:"monthly"`
+	Lifetime  map[string]Flow `json:"lifetime"`
+
+	DailyFlows    Flows `json:"daily_flows"`
+	MonthlyFlows  Flows `json:"monthly_flows"`
+	LifetimeFlows Flows `json:"lifetime_flows"`
+}
+
+// Accountant turns successive snapshots into energy totals
+type Accountant struct {
+	path         string
+	timezone     string
+	maxPower     float64
+	saveInterval time.Duration
+	saved        time.Time
+
+	// mu guards the state against Close racing with the poll loop
+	mu     sync.Mutex
+	closed bool
+	state  state
+}
+
+// New loads the accountant's state, if any was saved, so accounting resumes
+// where it stopped
+func New(conf *config.Configuration) (*Accountant, error) {
+	if conf.Energy.MaxPowerKw == 0 {
+		conf.Energy.MaxPowerKw = 100
+	}
+	if conf.Energy.SaveInterval == 0 {
+		conf.Energy.SaveInterval = time.Minute
+	}
+
+	a := &Accountant{
+		path:         conf.Energy.StateFile,
+		timezone:     conf.Energy.Timezone,
+		maxPower:     conf.Energy.MaxPowerKw * 1000,
+		saveInterval: conf.Energy.SaveInterval,
+	}
+
+	_, err := Location(a.timezone, model.Teg{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = LoadState(a.path, &a.state)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// LoadState reads a JSON state file into v, leaving v untouched if the file
+// does not exist yet
+func LoadState(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error when reading state file %s, %s", path, err)
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("error when parsing state file %s, %s", path, err)
+	}
+	return nil
+}
+
+// SaveState writes v as JSON to a state file, replacing it atomically
+func SaveState(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error when encoding state, %s", err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("error when creating state file, %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error when writing state file %s, %s", tmp.Name(), err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("error when replacing state file %s, %s", path, err)
+	}
+	return nil
+}
+
+// Close saves the state; snapshots arriving afterwards are ignored
+func (a *Accountant) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	return SaveState(a.path, a.state)
+}
+
+// locations caches the loaded time zones
+var locations sync.Map
+
+// Location returns the time zone totals are kept in: timezone if set, else
+// the site's, else the local time zone
+func Location(timezone string, metrics model.Teg) (*time.Location, error) {
+	name := timezone
+	if name == "" {
+		name = metrics.SiteInfo.Timezone
+	}
+	if name == "" {
+		return time.Local, nil
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("error when loading time zone %s, %s", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// counters reads the cumulative counters of each meter
+func counters(metrics model.Teg) map[string]Flow {
+	meters := map[string]model.TegMetersAggregate{
+		Site:    metrics.Meters.Site,
+		Battery: metrics.Meters.Battery,
+		Load:    metrics.Meters.Load,
+		Solar:   metrics.Meters.Solar,
+	}
+	c := map[string]Flow{}
+	for name, meter := range meters {
+		c[name] = Flow{Imported: meter.EnergyImportedWatts, Exported: meter.EnergyExportedWatts}
+	}
+	return c
+}
+
+// delta is how much a counter advanced; a counter which went backwards was
+// reset and counts from zero, and one which advanced faster than limit
+// allows is rebaselined
+func delta(last, current, limit float64) (float64, string) {
+	d := current - last
+	if d < 0 {
+		if current <= limit {
+			return current, ResetCounter
+		}
+		return 0, ResetCounter
+	}
+	if d > limit {
+		return 0, ResetImplausible
+	}
+	return d, ""
+}
+
+func copyFlows(flows map[string]Flow) map[string]Flow {
+	c := make(map[string]Flow, len(flows))
+	for key, value := range flows {
+		c[key] = value
+	}
+	return c
+}
+
+// Update accounts the energy since the previous snapshot and returns the
+// totals; an interval spanning midnight is split between the days in
+// proportion to time, returning the final totals of each day it closes
+// before the totals of the current day
+func (a *Accountant) Update(metrics model.Teg) ([]Totals, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil, nil
+	}
+
+	ts := metrics.Meters.Timestamp
+	loc, err := Location(a.timezone, metrics)
+	if err != nil {
+		return nil, err
+	}
+	current := counters(metrics)
+	s := &a.state
+
+	if s.Counters != nil && !ts.After(s.Time) {
+		return nil, nil
+	}
+
+	deltas := map[string]Flow{}
+	resets := map[string]string{}
+	switch {
+	case s.Counters == nil:
+		// Lifetime totals start from the gateway's counters and carry on
+		// from there across resets and replacements
+		s.Lifetime = copyFlows(current)
+	case metrics.Status.GatewayID != "" && s.GatewayID != "" && metrics.Status.GatewayID != s.GatewayID:
+		for _, m := range Meters {
+			resets[m] = ResetGateway
+		}
+	default:
+		limit := a.maxPower * ts.Sub(s.Time).Hours()
+		for _, m := range Meters {
+			imported, importReset := delta(s.Counters[m].Imported, current[m].Imported, limit)
+			exported, exportReset := delta(s.Counters[m].Exported, current[m].Exported, limit)
+			deltas[m] = Flow{Imported: imported, Exported: exported}
+			if importReset != "" {
+				resets[m] = importReset
+			} else if exportReset != "" {
+				resets[m] = exportReset
+			}
+		}
+	}
+
+	start := s.Time
+	if s.Counters == nil {
+		start = ts
+	}
+	if metrics.Status.GatewayID != "" {
+		s.GatewayID = metrics.Status.GatewayID
+	}
+	s.Time = ts
+	s.Counters = current
+	if s.Date == "" {
+		s.Date = ts.In(loc).Format(DateLayout)
+		s.Month = ts.In(loc).Format(MonthLayout)
+	}
+	if s.Daily == nil {
+		s.Daily = map[string]Flow{}
+		s.Monthly = map[string]Flow{}
+	}
+
+	var totals []Totals
+	elapsed := ts.Sub(start)
+	for {
+		day, err := time.ParseInLocation(DateLayout, s.Date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("error when parsing date %s, %s", s.Date, err)
+		}
+		midnight := day.AddDate(0, 0, 1)
+		end := ts
+		if midnight.Before(ts) {
+			end = midnight
+		}
+
+		portion := 1.0
+		if elapsed > 0 {
+			portion = float64(end.Sub(start)) / float64(elapsed)
+		}
+		interval := map[string]Flow{}
+		for m, d := range deltas {
+			interval[m] = d.scale(portion)
+			s.Daily[m] = s.Daily[m].add(interval[m])
+			s.Monthly[m] = s.Monthly[m].add(interval[m])
+			s.Lifetime[m] = s.Lifetime[m].add(interval[m])
+		}
+		flows := attribute(interval)
+		s.DailyFlows = s.DailyFlows.add(flows)
+		s.MonthlyFlows = s.MonthlyFlows.add(flows)
+		s.LifetimeFlows = s.LifetimeFlows.add(flows)
+
+		t := Totals{
+			Start:         start,
+			End:           end,
+			Date:          s.Date,
+			Month:         s.Month,
+			Location:      loc,
+			Interval:      interval,
+			Daily:         copyFlows(s.Daily),
+			Monthly:       copyFlows(s.Monthly),
+			Lifetime:      copyFlows(s.Lifetime),
+			IntervalFlows: flows,
+			DailyFlows:    s.DailyFlows,
+			MonthlyFlows:  s.MonthlyFlows,
+			LifetimeFlows: s.LifetimeFlows,
+			Resets:        resets,
+		}
+		if end.Before(ts) || !ts.Before(midnight) {
+			t.Final = true
+			totals = append(totals, t)
+			resets = map[string]string{}
+			s.Date = midnight.Format(DateLayout)
+			s.Daily = map[string]Flow{}
+			s.DailyFlows = Flows{}
+			if month := midnight.Format(MonthLayout); month != s.Month {
+				s.Month = month
+				s.Monthly = map[string]Flow{}
+				s.MonthlyFlows = Flows{}
+			}
+			start = midnight
+			if end.Equal(ts) {
+				break
+			}
+			continue
+		}
+		totals = append(totals, t)
+		break
+	}
+
+	if time.Since(a.saved) >= a.saveInterval {
+		err = SaveState(a.path, a.state)
+		if err != nil {
+			return totals, err
+		}
+		a.saved = time.Now()
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,77 @@
+package energy
+
+import (
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+)
+
+// Flows attributes the energy of a period in Wh, or the instant power in W,
+// to where it came from and went to
+type Flows struct {
+	SolarToLoad    float64 `json:"solar_to_load"`
+	SolarToBattery float64 `json:"solar_to_battery"`
+	SolarToGrid    float64 `json:"solar_to_grid"`
+	BatteryToLoad  float64 `json:"battery_to_load"`
+	BatteryToGrid  float64 `json:"battery_to_grid"`
+	GridToLoad     float64 `json:"grid_to_load"`
+	GridToBattery  float64 `json:"grid_to_battery"`
+}
+
+func (f Flows) add(g Flows) Flows {
+	return Flows{
+		SolarToLoad:    f.SolarToLoad + g.SolarToLoad,
+		SolarToBattery: f.SolarToBattery + g.SolarToBattery,
+		SolarToGrid:    f.SolarToGrid + g.SolarToGrid,
+		BatteryToLoad:  f.BatteryToLoad + g.BatteryToLoad,
+		BatteryToGrid:  f.BatteryToGrid + g.BatteryToGrid,
+		GridToLoad:     f.GridToLoad + g.GridToLoad,
+		GridToBattery:  f.GridToBattery + g.GridToBattery,
+	}
+}
+
+// Attribute splits solar generation, load and battery charge and discharge
+// between the sources and sinks by priority: solar supplies the load first,
+// then charges the battery, then is exported; the battery supplies what load
+// remains, then exports; the grid supplies the rest of the load and the rest
+// of the charge. Negative inputs are taken as zero.
+func Attribute(solar, load, charge, discharge float64) Flows {
+	solar = math.Max(solar, 0)
+	load = math.Max(load, 0)
+	charge = math.Max(charge, 0)
+	discharge = math.Max(discharge, 0)
+
+	var f Flows
+	f.SolarToLoad = math.Min(solar, load)
+	f.BatteryToLoad = math.Min(discharge, load-f.SolarToLoad)
+	f.GridToLoad = load - f.SolarToLoad - f.BatteryToLoad
+	f.SolarToBattery = math.Min(solar-f.SolarToLoad, charge)
+	f.GridToBattery = charge - f.SolarToBattery
+	f.SolarToGrid = solar - f.SolarToLoad - f.SolarToBattery
+	f.BatteryToGrid = discharge - f.BatteryToLoad
+	return f
+}
+
+// Map returns the flows keyed by source_to_sink
+func (f Flows) Map() map[string]float64 {
+	return map[string]float64{
+		"solar_to_load":    f.SolarToLoad,
+		"solar_to_battery": f.SolarToBattery,
+		"solar_to_grid":    f.SolarToGrid,
+		"battery_to_load":  f.BatteryToLoad,
+		"battery_to_grid":  f.BatteryToGrid,
+		"grid_to_load":     f.GridToLoad,
+		"grid_to_battery":  f.GridToBattery,
+	}
+}
+
+// PowerFlows splits the instant power of a snapshot; the battery charges
+// while its power is negative and discharges while it is positive
+func PowerFlows(metrics model.Teg) Flows {
+	battery := metrics.Meters.Battery.InstantPowerWatts
+	return Attribute(metrics.Meters.Solar.InstantPowerWatts, metrics.Meters.Load.InstantPowerWatts, -battery, battery)
+}
+
+// attribute splits the energy through the meters over a period
+func attribute(meters map[string]Flow) Flows {
+	return Attribute(meters[Solar].Exported, meters[Load].Imported, meters[Battery].Imported, meters[Battery].Exported)
+}
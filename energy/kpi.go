@@ -0,0 +1,48 @@
+package energy
+
+// ratio divides, reporting whether the ratio is defined
+func ratio(numerator, denominator float64) (float64, bool) {
+	if denominator <= 0 {
+		return 0, false
+	}
+	return numerator / denominator, true
+}
+
+// KPIs derives the site's ratios over a period from the attributed flows, as
+// fractions keyed by name; a ratio with nothing to divide by is left out
+//
+//   - self_consumption: share of solar generation used on site, directly or
+//     through the battery
+//   - self_sufficiency: share of the load supplied by solar and the battery
+//   - solar_fraction: share of the load supplied directly by solar
+//   - battery_fraction: share of the load supplied by the battery
+//   - grid_dependence: share of the load supplied by the grid
+func KPIs(flows Flows) map[string]float64 {
+	kpis := map[string]float64{}
+	solar := flows.SolarToLoad + flows.SolarToBattery + flows.SolarToGrid
+	load := flows.SolarToLoad + flows.BatteryToLoad + flows.GridToLoad
+
+	if v, ok := ratio(flows.SolarToLoad+flows.SolarToBattery, solar); ok {
+		kpis["self_consumption"] = v
+	}
+	if v, ok := ratio(flows.SolarToLoad+flows.BatteryToLoad, load); ok {
+		kpis["self_sufficiency"] = v
+	}
+	if v, ok := ratio(flows.SolarToLoad, load); ok {
+		kpis["solar_fraction"] = v
+	}
+	if v, ok := ratio(flows.BatteryToLoad, load); ok {
+		kpis["battery_fraction"] = v
+	}
+	if v, ok := ratio(flows.GridToLoad, load); ok {
+		kpis["grid_dependence"] = v
+	}
+	return kpis
+}
+
+// RoundTripEfficiency is the energy discharged from the battery over the
+// energy charged into it; it only settles over periods long enough for the
+// state of energy to return to where it started
+func RoundTripEfficiency(meters map[string]Flow) (float64, bool) {
+	return ratio(meters[Battery].Exported, meters[Battery].Imported)
+}
@@ -0,0 +1,233 @@
+// Package events keeps the last known operational settings and identity of
+// the site and reports a discrete event whenever one changes.
+package events
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the events
+const (
+	RealMode             = "real_mode"
+	BackupReservePercent = "backup_reserve_percent"
+	SitemasterStatus     = "sitemaster_status"
+	SitemasterRunning    = "sitemaster_running"
+	NetMeterMode         = "net_meter_mode"
+	FirmwareVersion      = "firmware_version"
+	BatteryBlocks        = "battery_blocks"
+	GatewayReboot        = "gateway_reboot"
+)
+
+// rebootTolerance is how far the gateway's start time may drift, as its
+// clock is adjusted, before it counts as a reboot
+const rebootTolerance = time.Minute
+
+// Event is a change of a value from Old to New
+type Event struct {
+	Time time.Time
+	Name string
+	Old  string
+	New  string
+	Text string
+}
+
+// state is what the Log persists across restarts
+type state struct {
+	Values    map[string]string `json:"values"`
+	StartTime time.Time         `json:"start_time"`
+	Uptime    time.Duration     `json:"uptime"`
+}
+
+// Log compares each snapshot with the last known values
+type Log struct {
+	path string
+
+	// mu guards the last known values, which Close saves while the poll loop
+	// may still be comparing a snapshot with them
+	mu     sync.Mutex
+	closed bool
+	state  state
+}
+
+// New loads the last known values, if any were saved, so changes made while
+// the collector was stopped are reported when it starts
+func New(conf *config.Configuration) (*Log, error) {
+	l := &Log{
+		path:  conf.Events.StateFile,
+		state: state{Values: map[string]string{}},
+	}
+	err := energy.LoadState(l.path, &l.state)
+	if err != nil {
+		return nil, err
+	}
+	if l.state.Values == nil {
+		l.state.Values = map[string]string{}
+	}
+	return l, nil
+}
+
+// Close saves the last known values; snapshots arriving afterwards are
+// ignored
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.save()
+}
+
+func (l *Log) save() error {
+	return energy.SaveState(l.path, l.state)
+}
+
+// serials lists the serial numbers of the battery blocks, sorted
+func serials(metrics model.Teg) []string {
+	var s []string
+	for _, block := range metrics.SystemStatus.BatteryBlocks {
+		if block.PackageSerialNumber != "" {
+			s = append(s, block.PackageSerialNumber)
+		}
+	}
+	sort.Strings(s)
+	return s
+}
+
+// blocksText describes the blocks added to and removed from the inventory
+func blocksText(prev, next string) string {
+	before := map[string]bool{}
+	for _, serial := range strings.Split(prev, ",") {
+		before[serial] = true
+	}
+	var added, removed []string
+	for _, serial := range strings.Split(next, ",") {
+		if !before[serial] {
+			added = append(added, serial)
+		}
+		delete(before, serial)
+	}
+	for serial := range before {
+		if serial != "" {
+			removed = append(removed, serial)
+		}
+	}
+	sort.Strings(removed)
+
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(removed, ", "))
+	}
+	return "battery blocks " + strings.Join(parts, "; ")
+}
+
+// Update compares a snapshot with the last known values and returns an event
+// for each that changed; values which are empty in the snapshot are left as
+// they were. The values are saved whenever one is first seen or changes.
+func (l *Log) Update(metrics model.Teg) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, nil
+	}
+
+	ts := metrics.Meters.Timestamp
+	var blocks string
+	if len(metrics.SystemStatus.BatteryBlocks) > 0 {
+		blocks = strings.Join(serials(metrics), ",")
+	}
+	var backupReserve string
+	if metrics.Operation.RealMode != "" {
+		backupReserve = strconv.FormatFloat(metrics.Operation.BackupReservePercent, 'f', -1, 64)
+	}
+	var sitemasterRunning string
+	if metrics.Sitemaster.Status != "" {
+		sitemasterRunning = strconv.FormatBool(metrics.Sitemaster.Running)
+	}
+
+	values := []struct {
+		name  string
+		value string
+	}{
+		{RealMode, metrics.Operation.RealMode},
+		{BackupReservePercent, backupReserve},
+		{SitemasterStatus, metrics.Sitemaster.Status},
+		{SitemasterRunning, sitemasterRunning},
+		{NetMeterMode, metrics.SiteInfo.NetMeterMode},
+		{FirmwareVersion, metrics.Status.FirmwareVersion},
+		{BatteryBlocks, blocks},
+	}
+
+	var events []Event
+	learned := false
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		old, known := l.state.Values[v.name]
+		l.state.Values[v.name] = v.value
+		if !known {
+			learned = true
+		}
+		if !known || old == v.value {
+			continue
+		}
+		e := Event{
+			Time: ts,
+			Name: v.name,
+			Old:  old,
+			New:  v.value,
+			Text: fmt.Sprintf("%s changed from %s to %s", v.name, old, v.value),
+		}
+		if v.name == BatteryBlocks {
+			e.Text = blocksText(old, v.value)
+		}
+		events = append(events, e)
+	}
+
+	// A reboot moves the start time forward, or failing that the uptime back
+	status := metrics.Status
+	rebooted := false
+	switch {
+	case !status.StartTime.IsZero() && !l.state.StartTime.IsZero():
+		rebooted = status.StartTime.Sub(l.state.StartTime) > rebootTolerance
+	case status.Uptime > 0 && l.state.Uptime > 0:
+		rebooted = status.Uptime < l.state.Uptime
+	}
+	if rebooted {
+		e := Event{
+			Time: ts,
+			Name: GatewayReboot,
+			Old:  l.state.StartTime.Format(time.RFC3339),
+			New:  status.StartTime.Format(time.RFC3339),
+			Text: fmt.Sprintf("gateway rebooted, up for %s", status.Uptime.Round(time.Second)),
+		}
+		if status.StartTime.IsZero() {
+			e.Old = l.state.Uptime.Round(time.Second).String()
+			e.New = status.Uptime.Round(time.Second).String()
+		}
+		events = append(events, e)
+	}
+	if !status.StartTime.IsZero() {
+		l.state.StartTime = status.StartTime
+	}
+	if status.Uptime > 0 {
+		l.state.Uptime = status.Uptime
+	}
+
+	if len(events) > 0 || learned {
+		return events, l.save()
+	}
+	return nil, nil
+}
@@ -0,0 +1,234 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	lp "github.com/influxdata/line-protocol"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultAggregated are the measurements aggregated when none are configured
+var defaultAggregated = []string{"energy_meters", "energy_powerwalls"}
+
+// evictWindows is how many windows a series may go without a point before its
+// window is written and the series is forgotten
+const evictWindows = 3
+
+// defaultLastFields are the suffixes of float fields which are counters or
+// times, so only their last value in a window is meaningful
+var defaultLastFields = []string{"energy_exported", "energy_imported", "energy_charged", "energy_discharged", "_time", "_timestamp"}
+
+// aggregateField accumulates one field over a window
+type aggregateField struct {
+	aggregate bool
+	sum       float64
+	min       float64
+	max       float64
+	n         int64
+	last      interface{}
+}
+
+// aggregateSeries accumulates the points of one series within a window
+type aggregateSeries struct {
+	start   time.Time
+	name    string
+	tags    []*lp.Tag
+	samples int64
+	fields  map[string]*aggregateField
+	order   []string
+}
+
+// aggregator is a WriteAPI which aggregates points of the configured
+// measurements over wall-clock aligned windows
+type aggregator struct {
+	next         influxAPI.WriteAPI
+	window       time.Duration
+	measurements map[string]bool
+	lastFields   []string
+
+	mu     sync.Mutex
+	series map[string]*aggregateSeries
+	swept  time.Time
+}
+
+// NewAggregator returns a WriteAPI which holds the points of the configured
+// measurements in memory and writes one point per series and window to next,
+// carrying the last value of every field under its own key, so its type is
+// unchanged, and the mean, min and max of numeric fields under the _mean, _min
+// and _max keys, plus the sample count; counters and times only keep their
+// last value. A window is
+// written once a sample from a later window arrives or on Flush. Other
+// measurements and line protocol records are passed through.
+func NewAggregator(conf *config.Configuration, next influxAPI.WriteAPI) influxAPI.WriteAPI {
+	measurements := conf.Aggregation.Measurements
+	if len(measurements) == 0 {
+		measurements = defaultAggregated
+	}
+	lastFields := conf.Aggregation.LastFields
+	if len(lastFields) == 0 {
+		lastFields = defaultLastFields
+	}
+
+	a := &aggregator{
+		next:         next,
+		window:       conf.Aggregation.Window,
+		measurements: map[string]bool{},
+		lastFields:   lastFields,
+		series:       map[string]*aggregateSeries{},
+	}
+	for _, m := range measurements {
+		a.measurements[conf.InfluxDB.MeasurementPrefix+m] = true
+	}
+	return a
+}
+
+func (a *aggregator) isLastField(field string) bool {
+	for _, suffix := range a.lastFields {
+		if strings.HasSuffix(field, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WritePoint adds a point to its series' window, writing the previous window
+// if the point starts a new one
+func (a *aggregator) WritePoint(point *write.Point) {
+	if !a.measurements[point.Name()] {
+		a.next.WritePoint(point)
+		return
+	}
+
+	start := point.Time().Truncate(a.window)
+	id := seriesID(point)
+
+	var done *write.Point
+	a.mu.Lock()
+	s, ok := a.series[id]
+	if ok && !s.start.Equal(start) {
+		done = s.point()
+		ok = false
+	}
+	if !ok {
+		s = &aggregateSeries{
+			start:  start,
+			name:   point.Name(),
+			tags:   point.TagList(),
+			fields: map[string]*aggregateField{},
+		}
+		a.series[id] = s
+	}
+	s.add(point, a.isLastField)
+	evicted := a.evict(start)
+	a.mu.Unlock()
+
+	if done != nil {
+		a.next.WritePoint(done)
+	}
+	for _, p := range evicted {
+		a.next.WritePoint(p)
+	}
+}
+
+// evict removes the series which have had no point for evictWindows windows
+// before start, such as a Powerwall which is no longer reported, returning
+// their last windows; the series are swept once per window
+func (a *aggregator) evict(start time.Time) []*write.Point {
+	if !start.After(a.swept) {
+		return nil
+	}
+	a.swept = start
+
+	var points []*write.Point
+	cutoff := start.Add(-evictWindows * a.window)
+	for id, s := range a.series {
+		if s.start.Before(cutoff) {
+			points = append(points, s.point())
+			delete(a.series, id)
+		}
+	}
+	return points
+}
+
+func (s *aggregateSeries) add(point *write.Point, isLastField func(string) bool) {
+	s.samples++
+	for _, field := range point.FieldList() {
+		f, ok := s.fields[field.Key]
+		if !ok {
+			value, isNumber := numeric(field.Value)
+			f = &aggregateField{
+				aggregate: isNumber && !isLastField(field.Key),
+				min:       value,
+				max:       value,
+			}
+			s.fields[field.Key] = f
+			s.order = append(s.order, field.Key)
+		}
+		f.last = field.Value
+		if !f.aggregate {
+			continue
+		}
+		value, ok := numeric(field.Value)
+		if !ok {
+			continue
+		}
+		f.sum += value
+		f.min = math.Min(f.min, value)
+		f.max = math.Max(f.max, value)
+		f.n++
+	}
+}
+
+// point renders the window as a point at the start of the window
+func (s *aggregateSeries) point() *write.Point {
+	p := write.NewPointWithMeasurement(s.name).SetTime(s.start)
+	for _, tag := range s.tags {
+		p.AddTag(tag.Key, tag.Value)
+	}
+	for _, key := range s.order {
+		f := s.fields[key]
+		p.AddField(key, f.last)
+		if f.aggregate && f.n > 0 {
+			p.AddField(key+"_mean", f.sum/float64(f.n))
+			p.AddField(key+"_min", f.min)
+			p.AddField(key+"_max", f.max)
+		}
+	}
+	p.AddField("sample_count", s.samples)
+	return p.SortFields()
+}
+
+// Flush writes the windows in progress and flushes the next WriteAPI
+func (a *aggregator) Flush() {
+	a.mu.Lock()
+	points := make([]*write.Point, 0, len(a.series))
+	for id, s := range a.series {
+		points = append(points, s.point())
+		delete(a.series, id)
+	}
+	a.mu.Unlock()
+
+	for _, p := range points {
+		a.next.WritePoint(p)
+	}
+	a.next.Flush()
+}
+
+// WriteRecord passes a line protocol record through
+func (a *aggregator) WriteRecord(line string) {
+	a.next.WriteRecord(line)
+}
+
+// Errors returns the errors of the next WriteAPI
+func (a *aggregator) Errors() <-chan error {
+	return a.next.Errors()
+}
+
+// SetWriteFailedCallback sets the callback on the next WriteAPI
+func (a *aggregator) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+	a.next.SetWriteFailedCallback(cb)
+}
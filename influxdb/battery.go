@@ -0,0 +1,58 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/battery"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+)
+
+// WriteBatteryHealth writes the health of the blocks whose day closed
+func WriteBatteryHealth(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, health []battery.Health) error {
+	for _, p := range BatteryHealthPoints(conf, metrics, health) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// BatteryHealthPoints lays out the daily health of each block as a
+// battery_health point; energies are in Wh
+func BatteryHealthPoints(conf *config.Configuration, metrics model.Teg, health []battery.Health) []*write.Point {
+	var points []*write.Point
+
+	b := newPointBuilder(conf, metadata(metrics))
+
+	for _, h := range health {
+		fields := map[string]interface{}{
+			"date":                   h.Date,
+			"samples":                h.Samples,
+			"nameplate_energy":       h.NameplateWh,
+			"capacity":               h.CapacityWh,
+			"capacity_min":           h.CapacityMinWh,
+			"capacity_max":           h.CapacityMaxWh,
+			"state_of_health":        h.StateOfHealth,
+			"day_energy_charged":     h.DayChargedWh,
+			"day_energy_discharged":  h.DayDischargedWh,
+			"energy_charged":         h.ChargedWh,
+			"energy_discharged":      h.DischargedWh,
+			"lifetime_throughput":    h.ChargedWh + h.DischargedWh,
+			"equivalent_full_cycles": h.EquivalentFullCycles,
+		}
+		if h.DegradationEstimated {
+			fields["degradation_percent_per_year"] = h.DegradationPercentPerYear
+		}
+		p := b.point(
+			conf.InfluxDB.MeasurementPrefix+"battery_health",
+			map[string]string{
+				"powerwall_serial_number": h.Serial,
+				"powerwall_part_number":   h.PartNumber,
+			},
+			fields,
+			h.Time,
+		)
+		points = append(points, p)
+	}
+
+	return points
+}
@@ -0,0 +1,376 @@
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	influx "github.com/influxdata/influxdb-client-go/v2"
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// downsampledMeasurements are the high frequency measurements rolled up by the
+// downsampling continuous queries (v1) or tasks (v2)
+var downsampledMeasurements = []string{"energy_meters", "energy_powerwalls"}
+
+// Bootstrap creates the configured buckets (v2) or databases and retention
+// policies (v1) and installs the downsampling tasks (v2) or continuous queries
+// (v1); existing objects are updated in place so it is safe to re-run. For
+// InfluxDB 3 only the databases are created.
+func Bootstrap(conf *config.Configuration) error {
+	if conf.InfluxDB.Version == 3 {
+		return bootstrapV3(conf)
+	} else if conf.InfluxDB.Token != "" {
+		return bootstrapV2(conf)
+	}
+	return bootstrapV1(conf)
+}
+
+// measurementDestination returns the bucket or database/retention policy a
+// measurement is written to after applying the configured routes
+func measurementDestination(conf *config.Configuration, measurement string) (string, string, string) {
+	for _, route := range conf.InfluxDB.Routes {
+		for _, m := range route.Measurements {
+			if m == measurement {
+				return route.Bucket, route.Database, route.RetentionPolicy
+			}
+		}
+	}
+	return conf.InfluxDB.Bucket, conf.InfluxDB.Database, conf.InfluxDB.RetentionPolicy
+}
+
+// formatDuration renders a duration in the largest whole unit, which both
+// InfluxQL and Flux accept as a duration literal
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
+// httpClient returns a client for the InfluxDB HTTP API with its own
+// transport, so the TLS settings of the gateway client are left alone
+func httpClient(conf *config.Configuration) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: conf.InfluxDB.SkipVerifySsl},
+		},
+	}
+}
+
+func bootstrapV1(conf *config.Configuration) error {
+	client := httpClient(conf)
+	defer client.CloseIdleConnections()
+
+	if conf.InfluxDB.Database == "" || conf.InfluxDB.RetentionPolicy == "" {
+		return fmt.Errorf("database and retention policy are required to bootstrap InfluxDB v1")
+	}
+
+	err := ensureRetentionPolicyV1(conf, client, conf.InfluxDB.Database, conf.InfluxDB.RetentionPolicy, conf.InfluxDB.Bootstrap.Retention)
+	if err != nil {
+		return err
+	}
+
+	for i, route := range conf.InfluxDB.Routes {
+		if route.Database == "" || route.RetentionPolicy == "" {
+			return fmt.Errorf("route %d requires a database and retention policy to bootstrap InfluxDB v1", i)
+		}
+		err = ensureRetentionPolicyV1(conf, client, route.Database, route.RetentionPolicy, route.Retention)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ds := range conf.InfluxDB.Bootstrap.Downsample {
+		if ds.Every <= 0 || ds.RetentionPolicy == "" {
+			return fmt.Errorf("downsampling requires an interval and a retention policy for InfluxDB v1")
+		}
+		for _, measurement := range downsampledMeasurements {
+			_, database, retentionPolicy := measurementDestination(conf, measurement)
+			err = ensureRetentionPolicyV1(conf, client, database, ds.RetentionPolicy, ds.Retention)
+			if err != nil {
+				return err
+			}
+
+			name := fmt.Sprintf("%s%s_%s", conf.InfluxDB.MeasurementPrefix, measurement, formatDuration(ds.Every))
+			query := fmt.Sprintf(`CREATE CONTINUOUS QUERY "%s" ON "%s" BEGIN SELECT mean(*) INTO "%s"."%s"."%s" FROM "%s"."%s"."%s" GROUP BY time(%s), * END`,
+				name, database,
+				database, ds.RetentionPolicy, conf.InfluxDB.MeasurementPrefix+measurement,
+				database, retentionPolicy, conf.InfluxDB.MeasurementPrefix+measurement,
+				formatDuration(ds.Every))
+			err = influxQL(conf, client, query)
+			if err != nil && strings.Contains(err.Error(), "already exists") {
+				// The definition changed, continuous queries cannot be altered
+				err = influxQL(conf, client, fmt.Sprintf(`DROP CONTINUOUS QUERY "%s" ON "%s"`, name, database))
+				if err == nil {
+					err = influxQL(conf, client, query)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("error when creating continuous query %s, %s", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureRetentionPolicyV1 creates the database and retention policy, altering
+// the duration of an existing retention policy; a zero retention is infinite
+func ensureRetentionPolicyV1(conf *config.Configuration, client *http.Client, database, retentionPolicy string, retention time.Duration) error {
+	err := influxQL(conf, client, fmt.Sprintf(`CREATE DATABASE "%s"`, database))
+	if err != nil {
+		return fmt.Errorf("error when creating database %s, %s", database, err)
+	}
+
+	duration := "INF"
+	if retention > 0 {
+		duration = formatDuration(retention)
+	}
+	err = influxQL(conf, client, fmt.Sprintf(`CREATE RETENTION POLICY "%s" ON "%s" DURATION %s REPLICATION 1`, retentionPolicy, database, duration))
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		err = influxQL(conf, client, fmt.Sprintf(`ALTER RETENTION POLICY "%s" ON "%s" DURATION %s`, retentionPolicy, database, duration))
+	}
+	if err != nil {
+		return fmt.Errorf("error when creating retention policy %s on %s, %s", retentionPolicy, database, err)
+	}
+
+	return nil
+}
+
+// influxQL runs a single InfluxQL management statement against the v1 API
+func influxQL(conf *config.Configuration, client *http.Client, query string) error {
+	req, err := http.NewRequest("POST", conf.InfluxDB.Address+"/query", strings.NewReader(url.Values{"q": {query}}.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if conf.InfluxDB.Username != "" && conf.InfluxDB.Password != "" {
+		req.SetBasicAuth(conf.InfluxDB.Username, conf.InfluxDB.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		Error   string `json:"error"`
+		Results []struct {
+			Error string `json:"error"`
+		} `json:"results"`
+	}{}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("unexpected HTTP status code %d; raw body %s", resp.StatusCode, body)
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
+	for _, r := range result.Results {
+		if r.Error != "" {
+			return fmt.Errorf("%s", r.Error)
+		}
+	}
+
+	return nil
+}
+
+func bootstrapV2(conf *config.Configuration) error {
+	ctx := context.Background()
+	options := influx.DefaultOptions().
+		SetTLSConfig(&tls.Config{
+			InsecureSkipVerify: conf.InfluxDB.SkipVerifySsl,
+		})
+	client := influx.NewClientWithOptions(conf.InfluxDB.Address, conf.InfluxDB.Token, options)
+	defer client.Close()
+
+	org, err := client.OrganizationsAPI().FindOrganizationByName(ctx, conf.InfluxDB.Organization)
+	if err != nil {
+		return fmt.Errorf("error when looking up organization %s, %s", conf.InfluxDB.Organization, err)
+	}
+
+	if conf.InfluxDB.Bucket == "" {
+		return fmt.Errorf("bucket is required to bootstrap InfluxDB v2")
+	}
+	err = ensureBucketV2(ctx, client.BucketsAPI(), org, conf.InfluxDB.Bucket, conf.InfluxDB.Bootstrap.Retention)
+	if err != nil {
+		return err
+	}
+
+	for i, route := range conf.InfluxDB.Routes {
+		if route.Bucket == "" {
+			return fmt.Errorf("route %d requires a bucket to bootstrap InfluxDB v2", i)
+		}
+		err = ensureBucketV2(ctx, client.BucketsAPI(), org, route.Bucket, route.Retention)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ds := range conf.InfluxDB.Bootstrap.Downsample {
+		if ds.Every <= 0 || ds.Bucket == "" {
+			return fmt.Errorf("downsampling requires an interval and a bucket for InfluxDB v2")
+		}
+		err = ensureBucketV2(ctx, client.BucketsAPI(), org, ds.Bucket, ds.Retention)
+		if err != nil {
+			return err
+		}
+
+		for _, measurement := range downsampledMeasurements {
+			bucket, _, _ := measurementDestination(conf, measurement)
+			name := fmt.Sprintf("%s%s_%s", conf.InfluxDB.MeasurementPrefix, measurement, formatDuration(ds.Every))
+			flux := fmt.Sprintf(`import "types"
+
+option task = {name: "%s", every: %s}
+
+from(bucket: "%s")
+    |> range(start: -task.every)
+    |> filter(fn: (r) => r._measurement == "%s")
+    |> filter(fn: (r) => types.isType(v: r._value, type: "float") or types.isType(v: r._value, type: "int"))
+    |> aggregateWindow(every: task.every, fn: mean)
+    |> to(bucket: "%s", org: "%s")
+`, name, formatDuration(ds.Every), bucket, conf.InfluxDB.MeasurementPrefix+measurement, ds.Bucket, org.Name)
+			err = ensureTaskV2(ctx, client.TasksAPI(), org, name, flux, formatDuration(ds.Every))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureBucketV2 creates the bucket or updates the retention of an existing
+// one; a zero retention is infinite
+func ensureBucketV2(ctx context.Context, bucketsAPI influxAPI.BucketsAPI, org *domain.Organization, name string, retention time.Duration) error {
+	expire := domain.RetentionRuleTypeExpire
+	rule := domain.RetentionRule{EverySeconds: int64(retention / time.Second), Type: &expire}
+
+	bucket, err := bucketsAPI.FindBucketByName(ctx, name)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("error when looking up bucket %s, %s", name, err)
+		}
+		_, err = bucketsAPI.CreateBucketWithName(ctx, org, name, rule)
+		if err != nil {
+			return fmt.Errorf("error when creating bucket %s, %s", name, err)
+		}
+		return nil
+	}
+
+	if len(bucket.RetentionRules) == 1 && bucket.RetentionRules[0].EverySeconds == rule.EverySeconds {
+		return nil
+	}
+	bucket.RetentionRules = domain.RetentionRules{rule}
+	_, err = bucketsAPI.UpdateBucket(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("error when updating retention of bucket %s, %s", name, err)
+	}
+
+	return nil
+}
+
+// ensureTaskV2 creates the task or updates the Flux of an existing task with
+// the same name
+func ensureTaskV2(ctx context.Context, tasksAPI influxAPI.TasksAPI, org *domain.Organization, name, flux, every string) error {
+	tasks, err := tasksAPI.FindTasks(ctx, &influxAPI.TaskFilter{Name: name, OrgID: *org.Id})
+	if err != nil {
+		return fmt.Errorf("error when looking up task %s, %s", name, err)
+	}
+
+	if len(tasks) == 0 {
+		_, err = tasksAPI.CreateTaskByFlux(ctx, flux, *org.Id)
+		if err != nil {
+			return fmt.Errorf("error when creating task %s, %s", name, err)
+		}
+		return nil
+	}
+
+	task := tasks[0]
+	if task.Flux == flux {
+		return nil
+	}
+	task.Flux = flux
+	task.Every = &every
+	_, err = tasksAPI.UpdateTask(ctx, &task)
+	if err != nil {
+		return fmt.Errorf("error when updating task %s, %s", name, err)
+	}
+
+	return nil
+}
+
+func bootstrapV3(conf *config.Configuration) error {
+	if len(conf.InfluxDB.Bootstrap.Downsample) > 0 {
+		return fmt.Errorf("downsampling is not supported for InfluxDB 3")
+	}
+
+	client := httpClient(conf)
+	defer client.CloseIdleConnections()
+
+	if conf.InfluxDB.Bootstrap.Retention > 0 {
+		return fmt.Errorf("retention is not supported for InfluxDB 3")
+	}
+	databases := []string{conf.InfluxDB.Database}
+	for _, route := range conf.InfluxDB.Routes {
+		if route.Retention > 0 {
+			return fmt.Errorf("retention is not supported for InfluxDB 3")
+		}
+		databases = append(databases, route.Database)
+	}
+
+	for _, database := range databases {
+		if database == "" {
+			return fmt.Errorf("a database is required for every destination to bootstrap InfluxDB 3")
+		}
+
+		payload, err := json.Marshal(map[string]string{"db": database})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest("POST", strings.TrimSuffix(conf.InfluxDB.Address, "/")+"/api/v3/configure/database", bytes.NewBuffer(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if conf.InfluxDB.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+conf.InfluxDB.Token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error when creating database %s, %s", database, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		// A conflict means the database already exists
+		if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusConflict {
+			return fmt.Errorf("error when creating database %s, unexpected HTTP status code %d; raw body %s", database, resp.StatusCode, body)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	lp "github.com/influxdata/line-protocol"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFiltered are the measurements written by WriteAll, which are
+// filtered when none are configured; derived measurements such as the energy
+// and cost totals are written in full
+var defaultFiltered = []string{"energy_meters", "energy_powerwalls", "energy_configuration", "energy_network", "energy_faults", DashboardMeasurement}
+
+// deadband is how far a numeric field must move from its last written value
+// before it is written again
+type deadband struct {
+	absolute float64
+	percent  float64
+}
+
+// exceeded reports whether value has moved beyond the deadband around last;
+// with no deadband any change is enough
+func (d deadband) exceeded(last, value float64) bool {
+	delta := math.Abs(value - last)
+	if d.absolute == 0 && d.percent == 0 {
+		return delta != 0
+	}
+	if d.absolute > 0 && delta > d.absolute {
+		return true
+	}
+	return d.percent > 0 && delta > math.Abs(last)*d.percent/100
+}
+
+// seriesState holds the last written value of every field of a series
+type seriesState struct {
+	heartbeat time.Time
+	values    map[string]interface{}
+}
+
+// changeFilter is a WriteAPI which drops fields of the configured
+// measurements that have not changed since they were last written, writing
+// every field again each heartbeat
+type changeFilter struct {
+	next         influxAPI.WriteAPI
+	heartbeat    time.Duration
+	measurements map[string]bool
+	fallback     deadband
+	deadbands    map[string]deadband
+
+	mu     sync.Mutex
+	series map[string]*seriesState
+}
+
+// NewChangeFilter returns a WriteAPI which writes numeric fields of the
+// configured measurements to next only when they move beyond their deadband
+// and other fields only when they change; other measurements and line
+// protocol records are passed through unfiltered
+func NewChangeFilter(conf *config.Configuration, next influxAPI.WriteAPI) influxAPI.WriteAPI {
+	measurements := conf.ChangeFilter.Measurements
+	if len(measurements) == 0 {
+		measurements = defaultFiltered
+	}
+
+	f := &changeFilter{
+		next:         next,
+		heartbeat:    conf.ChangeFilter.Heartbeat,
+		measurements: map[string]bool{},
+		fallback:     deadband{absolute: conf.ChangeFilter.Absolute, percent: conf.ChangeFilter.Percent},
+		deadbands:    map[string]deadband{},
+		series:       map[string]*seriesState{},
+	}
+	for _, m := range measurements {
+		f.measurements[routedMeasurement(conf, m)] = true
+	}
+	for _, d := range conf.ChangeFilter.Deadbands {
+		for _, field := range d.Fields {
+			// Fields may be qualified by measurement as measurement.field
+			if strings.Contains(field, ".") {
+				field = conf.InfluxDB.MeasurementPrefix + field
+			}
+			f.deadbands[field] = deadband{absolute: d.Absolute, percent: d.Percent}
+		}
+	}
+	return f
+}
+
+func (f *changeFilter) deadband(measurement, field string) deadband {
+	if d, ok := f.deadbands[measurement+"."+field]; ok {
+		return d
+	}
+	if d, ok := f.deadbands[field]; ok {
+		return d
+	}
+	return f.fallback
+}
+
+// seriesID identifies a series by its measurement and sorted tags
+func seriesID(point *write.Point) string {
+	tags := point.TagList()
+	pairs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	sort.Strings(pairs)
+	return point.Name() + "," + strings.Join(pairs, ",")
+}
+
+func numeric(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// WritePoint writes the changed fields of a point, dropping it entirely if
+// none changed
+func (f *changeFilter) WritePoint(point *write.Point) {
+	if !f.measurements[point.Name()] {
+		f.next.WritePoint(point)
+		return
+	}
+
+	f.mu.Lock()
+	id := seriesID(point)
+	state, ok := f.series[id]
+	if !ok {
+		state = &seriesState{values: map[string]interface{}{}}
+		f.series[id] = state
+	}
+
+	heartbeat := point.Time().Sub(state.heartbeat) >= f.heartbeat
+	if heartbeat {
+		state.heartbeat = point.Time()
+	}
+
+	var changed []*lp.Field
+	for _, field := range point.FieldList() {
+		last, seen := state.values[field.Key]
+		keep := heartbeat || !seen
+		if !keep {
+			value, isNumber := numeric(field.Value)
+			lastValue, lastIsNumber := numeric(last)
+			if isNumber && lastIsNumber {
+				keep = f.deadband(point.Name(), field.Key).exceeded(lastValue, value)
+			} else {
+				keep = field.Value != last
+			}
+		}
+		if keep {
+			state.values[field.Key] = field.Value
+			changed = append(changed, field)
+		}
+	}
+	f.mu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	if len(changed) == len(point.FieldList()) {
+		f.next.WritePoint(point)
+		return
+	}
+
+	p := write.NewPointWithMeasurement(point.Name()).SetTime(point.Time())
+	for _, tag := range point.TagList() {
+		p.AddTag(tag.Key, tag.Value)
+	}
+	for _, field := range changed {
+		p.AddField(field.Key, field.Value)
+	}
+	f.next.WritePoint(p)
+}
+
+// WriteRecord passes a line protocol record through
+func (f *changeFilter) WriteRecord(line string) {
+	f.next.WriteRecord(line)
+}
+
+// Flush flushes the next WriteAPI
+func (f *changeFilter) Flush() {
+	f.next.Flush()
+}
+
+// Errors returns the errors of the next WriteAPI
+func (f *changeFilter) Errors() <-chan error {
+	return f.next.Errors()
+}
+
+// SetWriteFailedCallback sets the callback on the next WriteAPI
+func (f *changeFilter) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+	f.next.SetWriteFailedCallback(cb)
+}
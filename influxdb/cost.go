@@ -0,0 +1,61 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/tariff"
+)
+
+// WriteCosts writes the costs of the energy accounted from a snapshot
+func WriteCosts(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, costs []tariff.Cost) error {
+	for _, p := range CostPoints(conf, metrics, costs) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// CostPoints lays out costs as cost points; amounts are in the tariff's
+// currency. The time of use season and period are fields rather than tags so
+// the day and month running totals stay in one series.
+func CostPoints(conf *config.Configuration, metrics model.Teg, costs []tariff.Cost) []*write.Point {
+	var points []*write.Point
+
+	b := newPointBuilder(conf, metadata(metrics))
+
+	for _, c := range costs {
+		fields := map[string]interface{}{
+			"date":         c.Date,
+			"day_final":    c.Final,
+			"import_price": c.Price.Import,
+			"export_price": c.Price.Export,
+			"tou_season":   c.Price.Season,
+			"tou_period":   c.Price.Period,
+		}
+		periods := map[string]tariff.Amounts{
+			"interval": c.Interval,
+			"day":      c.Daily,
+			"month":    c.Monthly,
+		}
+		for period, a := range periods {
+			fields[period+"_import_cost"] = a.ImportCost
+			fields[period+"_export_credit"] = a.ExportCredit
+			fields[period+"_fixed_charge"] = a.FixedCharge
+			fields[period+"_net_cost"] = a.Net()
+			fields[period+"_baseline_cost"] = a.BaselineCost
+			fields[period+"_savings"] = a.Savings()
+		}
+		p := b.point(
+			conf.InfluxDB.MeasurementPrefix+"cost",
+			map[string]string{
+				"currency": conf.Tariff.Currency,
+			},
+			fields,
+			c.Time,
+		)
+		points = append(points, p)
+	}
+
+	return points
+}
@@ -0,0 +1,132 @@
+package influxdb
+
+import (
+	"fmt"
+	influx "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"sync"
+	"time"
+)
+
+// DashboardMeasurement is the measurement the Powerwall-Dashboard Telegraf
+// configuration writes to; it is never prefixed
+const DashboardMeasurement = "http"
+
+// Schema profiles selecting the points WriteAll emits
+const (
+	SchemaNative             = "native"
+	SchemaPowerwallDashboard = "powerwall-dashboard"
+	SchemaBoth               = "both"
+)
+
+// routedMeasurement applies the measurement prefix to a measurement named in
+// a route, except for the dashboard measurement
+func routedMeasurement(conf *config.Configuration, measurement string) string {
+	if measurement == DashboardMeasurement {
+		return measurement
+	}
+	return conf.InfluxDB.MeasurementPrefix + measurement
+}
+
+// locations caches the loaded dashboard time zones
+var locations sync.Map
+
+// checkSchema validates the configured schema profile and time zone
+func checkSchema(conf *config.Configuration) error {
+	switch conf.InfluxDB.Schema {
+	case "", SchemaNative:
+		return nil
+	case SchemaPowerwallDashboard, SchemaBoth:
+		_, err := dashboardLocation(conf)
+		return err
+	}
+	return fmt.Errorf("unsupported schema %s, must be one of %s, %s or %s",
+		conf.InfluxDB.Schema, SchemaNative, SchemaPowerwallDashboard, SchemaBoth)
+}
+
+func dashboardLocation(conf *config.Configuration) (*time.Location, error) {
+	if conf.InfluxDB.Timezone == "" {
+		return time.Local, nil
+	}
+	if loc, ok := locations.Load(conf.InfluxDB.Timezone); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(conf.InfluxDB.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error when loading time zone %s, %s", conf.InfluxDB.Timezone, err)
+	}
+	locations.Store(conf.InfluxDB.Timezone, loc)
+	return loc, nil
+}
+
+// DashboardPoints lays out the Teg data structure as the pypowerwall proxy
+// responses flattened by Telegraf, which is what the Powerwall-Dashboard
+// continuous queries and Grafana dashboards read
+func DashboardPoints(conf *config.Configuration, metrics model.Teg) []*write.Point {
+	loc, err := dashboardLocation(conf)
+	if err != nil {
+		loc = time.Local
+	}
+	ts := metrics.Meters.Timestamp.In(loc)
+
+	// Telegraf's JSON input stores every number as a float, so every numeric
+	// field is written as one to avoid field type conflicts
+	fields := map[string]interface{}{}
+
+	// /aggregates
+	meters := map[string]model.TegMetersAggregate{
+		"site":    metrics.Meters.Site,
+		"battery": metrics.Meters.Battery,
+		"load":    metrics.Meters.Load,
+		"solar":   metrics.Meters.Solar,
+	}
+	for name, meter := range meters {
+		fields[name+"_instant_power"] = meter.InstantPowerWatts
+		fields[name+"_instant_reactive_power"] = meter.InstantReactivePowerWatts
+		fields[name+"_instant_apparent_power"] = meter.InstantApparentPowerWatts
+		fields[name+"_frequency"] = meter.Frequency
+		fields[name+"_energy_exported"] = meter.EnergyExportedWatts
+		fields[name+"_energy_imported"] = meter.EnergyImportedWatts
+		fields[name+"_instant_average_voltage"] = meter.InstantAverageVoltage
+		fields[name+"_instant_average_current"] = meter.InstantAverageCurrent
+		fields[name+"_i_a_current"] = meter.IACurrent
+		fields[name+"_i_b_current"] = meter.IBCurrent
+		fields[name+"_i_c_current"] = meter.ICCurrent
+		fields[name+"_instant_total_current"] = meter.InstantTotalCurrent
+		fields[name+"_num_meters_aggregated"] = float64(meter.NumMetersAggregated)
+		fields[name+"_timeout"] = float64(meter.Timeout)
+	}
+
+	// /soe
+	fields["percentage"] = metrics.SystemStateOfEnergy.Percentage
+
+	// /freq
+	gridStatus := 0.0
+	if metrics.SystemGridStatus.GridStatus == "SystemGridConnected" {
+		gridStatus = 1.0
+	}
+	fields["grid_status"] = gridStatus
+
+	// /pod
+	fields["backup_reserve_percent"] = metrics.Operation.BackupReservePercent
+	fields["nominal_full_pack_energy"] = float64(metrics.SystemStatus.NominalFullPackEnergyWattHours)
+	fields["nominal_energy_remaining"] = float64(metrics.SystemStatus.NominalEnergyRemainingWattHours)
+	for i, block := range metrics.SystemStatus.BatteryBlocks {
+		pw := fmt.Sprintf("PW%d_POD_", i+1)
+		fields[pw+"nom_energy_remaining"] = float64(block.NominalEnergyRemainingWattHours)
+		fields[pw+"nom_full_pack_energy"] = float64(block.NominalFullPackEnergy)
+	}
+
+	tags := map[string]string{}
+	for key, value := range conf.Tags.Static {
+		tags[key] = value
+	}
+	tags["month"] = ts.Format("Jan")
+	tags["year"] = ts.Format("2006")
+
+	p := influx.NewPoint(DashboardMeasurement, tags, fields, metrics.Meters.Timestamp)
+
+	return []*write.Point{p}
+}
@@ -0,0 +1,64 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/demand"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+)
+
+// WriteDemand writes the demand at a poll
+func WriteDemand(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, d demand.Demand) error {
+	for _, p := range DemandPoints(conf, metrics, d) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// DemandPoints lays out the demand at a poll as a demand point, and each
+// window which ended as a demand point at the start of the window; demands
+// are in W
+func DemandPoints(conf *config.Configuration, metrics model.Teg, d demand.Demand) []*write.Point {
+	var points []*write.Point
+	if d.Time.IsZero() {
+		return points
+	}
+
+	b := newPointBuilder(conf, metadata(metrics))
+	measurement := conf.InfluxDB.MeasurementPrefix + "demand"
+
+	for _, w := range d.Closed {
+		points = append(points, b.point(
+			measurement,
+			nil,
+			map[string]interface{}{
+				"interval_demand": w.Demand,
+				"partial":         w.Partial,
+			},
+			w.Start,
+		))
+	}
+
+	fields := map[string]interface{}{
+		"billing_period":   d.Period,
+		"window_demand":    d.Window,
+		"projected_demand": d.Projected,
+		"peak_demand":      d.Peak,
+	}
+	if !d.PeakTime.IsZero() {
+		fields["peak_time"] = d.PeakTime.UnixNano()
+		fields["headroom_power"] = d.Headroom
+	}
+	if d.RollingFull {
+		fields["rolling_demand"] = d.Rolling
+	}
+	if conf.Demand.Price > 0 {
+		fields["demand_charge"] = d.Peak / 1000 * conf.Demand.Price
+		fields["projected_demand_charge"] = math.Max(d.Peak, d.Projected) / 1000 * conf.Demand.Price
+	}
+	points = append(points, b.point(measurement, nil, fields, d.Time))
+
+	return points
+}
@@ -0,0 +1,132 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+)
+
+// WriteEnergy writes the energy totals accounted from a snapshot
+func WriteEnergy(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, totals []energy.Totals) error {
+	for _, p := range EnergyPoints(conf, metrics, totals) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// EnergyPoints lays out energy totals as one energy_totals point per meter,
+// with energies in Wh, and the KPIs and flows derived from them as
+// energy_kpis and energy_flows points
+func EnergyPoints(conf *config.Configuration, metrics model.Teg, totals []energy.Totals) []*write.Point {
+	var points []*write.Point
+
+	b := newPointBuilder(conf, metadata(metrics))
+
+	for _, t := range totals {
+		for _, m := range energy.Meters {
+			fields := map[string]interface{}{
+				"interval_energy_imported": t.Interval[m].Imported,
+				"interval_energy_exported": t.Interval[m].Exported,
+				"day_energy_imported":      t.Daily[m].Imported,
+				"day_energy_exported":      t.Daily[m].Exported,
+				"month_energy_imported":    t.Monthly[m].Imported,
+				"month_energy_exported":    t.Monthly[m].Exported,
+				"lifetime_energy_imported": t.Lifetime[m].Imported,
+				"lifetime_energy_exported": t.Lifetime[m].Exported,
+				"date":                     t.Date,
+				"day_final":                t.Final,
+			}
+			if reason, ok := t.Resets[m]; ok {
+				fields["reset"] = reason
+			}
+			p := b.point(
+				conf.InfluxDB.MeasurementPrefix+"energy_totals",
+				map[string]string{
+					"meter": m,
+				},
+				fields,
+				t.Time(),
+			)
+			points = append(points, p)
+		}
+
+		if conf.Energy.KPIs {
+			points = append(points, b.point(
+				conf.InfluxDB.MeasurementPrefix+"energy_kpis",
+				nil,
+				kpiFields(t),
+				t.Time(),
+			))
+		}
+
+		if conf.Energy.Flows {
+			points = append(points, b.point(
+				conf.InfluxDB.MeasurementPrefix+"energy_flows",
+				nil,
+				flowFields(metrics, t),
+				t.Time(),
+			))
+		}
+	}
+
+	return points
+}
+
+// kpiFields prefixes the interval, day and month KPIs with their period; the
+// round trip efficiency is only meaningful over a day or longer
+func kpiFields(t energy.Totals) map[string]interface{} {
+	fields := map[string]interface{}{
+		"date":      t.Date,
+		"day_final": t.Final,
+	}
+	periods := map[string]energy.Flows{
+		"interval": t.IntervalFlows,
+		"day":      t.DailyFlows,
+		"month":    t.MonthlyFlows,
+	}
+	for period, flows := range periods {
+		for name, value := range energy.KPIs(flows) {
+			fields[period+"_"+name] = value
+		}
+	}
+	totals := map[string]map[string]energy.Flow{
+		"day":      t.Daily,
+		"month":    t.Monthly,
+		"lifetime": t.Lifetime,
+	}
+	for period, meters := range totals {
+		if value, ok := energy.RoundTripEfficiency(meters); ok {
+			fields[period+"_battery_round_trip_efficiency"] = value
+		}
+	}
+	return fields
+}
+
+// flowFields lays out the attributed flows as the instant power in W of the
+// snapshot the totals end at, and the interval, day, month and lifetime
+// energy in Wh
+func flowFields(metrics model.Teg, t energy.Totals) map[string]interface{} {
+	fields := map[string]interface{}{
+		"date":      t.Date,
+		"day_final": t.Final,
+	}
+	if t.End.Equal(metrics.Meters.Timestamp) {
+		for name, value := range energy.PowerFlows(metrics).Map() {
+			fields[name+"_power"] = value
+		}
+	}
+	periods := map[string]energy.Flows{
+		"interval": t.IntervalFlows,
+		"day":      t.DailyFlows,
+		"month":    t.MonthlyFlows,
+		"lifetime": t.LifetimeFlows,
+	}
+	for period, flows := range periods {
+		for name, value := range flows.Map() {
+			fields[period+"_"+name+"_energy"] = value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,43 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/events"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+)
+
+// WriteEvents writes the changes seen in a snapshot
+func WriteEvents(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, changes []events.Event) error {
+	for _, p := range EventPoints(conf, metrics, changes) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// EventPoints lays out each change as an events point tagged by the name of
+// the value which changed, with text to show as an annotation
+func EventPoints(conf *config.Configuration, metrics model.Teg, changes []events.Event) []*write.Point {
+	var points []*write.Point
+
+	b := newPointBuilder(conf, metadata(metrics))
+
+	for _, e := range changes {
+		p := b.point(
+			conf.InfluxDB.MeasurementPrefix+"events",
+			map[string]string{
+				"event": e.Name,
+			},
+			map[string]interface{}{
+				"old":  e.Old,
+				"new":  e.New,
+				"text": e.Text,
+			},
+			e.Time,
+		)
+		points = append(points, p)
+	}
+
+	return points
+}
@@ -0,0 +1,65 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"sync"
+)
+
+// fanOut is a WriteAPI which writes every point to each of its outputs
+type fanOut struct {
+	outputs []influxAPI.WriteAPI
+	errCh   chan error
+	errOnce sync.Once
+}
+
+// NewFanOut returns a WriteAPI which writes every point to each of the given
+// WriteAPIs, for example InfluxDB and the embedded SQLite store
+func NewFanOut(outputs ...influxAPI.WriteAPI) influxAPI.WriteAPI {
+	return &fanOut{
+		outputs: outputs,
+		errCh:   make(chan error),
+	}
+}
+
+// WriteRecord writes a line protocol record to every output
+func (f *fanOut) WriteRecord(line string) {
+	for _, output := range f.outputs {
+		output.WriteRecord(line)
+	}
+}
+
+// WritePoint writes a point to every output
+func (f *fanOut) WritePoint(point *write.Point) {
+	for _, output := range f.outputs {
+		output.WritePoint(point)
+	}
+}
+
+// Flush flushes every output
+func (f *fanOut) Flush() {
+	for _, output := range f.outputs {
+		output.Flush()
+	}
+}
+
+// Errors returns a single channel carrying the write errors of every output
+func (f *fanOut) Errors() <-chan error {
+	f.errOnce.Do(func() {
+		for _, output := range f.outputs {
+			go func(errorsCh <-chan error) {
+				for err := range errorsCh {
+					f.errCh <- err
+				}
+			}(output.Errors())
+		}
+	})
+	return f.errCh
+}
+
+// SetWriteFailedCallback sets the callback on every output
+func (f *fanOut) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+	for _, output := range f.outputs {
+		output.SetWriteFailedCallback(cb)
+	}
+}
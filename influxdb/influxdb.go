@@ -5,13 +5,43 @@ import (
 	"fmt"
 	influx "github.com/influxdata/influxdb-client-go/v2"
 	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/iwvelando/tesla-energy-stats-collector/config"
 	"github.com/iwvelando/tesla-energy-stats-collector/model"
 	"strings"
+	"time"
 )
 
+// Client is the part of an InfluxDB client used by the collector; it is
+// satisfied by the influxdb-client-go client (v1 and v2) and the v3 writer
+type Client interface {
+	Close()
+}
+
 // Connect authenticates to InfluxDB and returns a client
-func Connect(conf *config.Configuration) (influx.Client, influxAPI.WriteAPI, error) {
+func Connect(conf *config.Configuration) (Client, influxAPI.WriteAPI, error) {
+	precision, err := parsePrecision(conf.InfluxDB.Precision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checkSchema(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if conf.InfluxDB.FlushInterval == 0 {
+		conf.InfluxDB.FlushInterval = 30
+	}
+
+	if conf.InfluxDB.BatchSize == 0 {
+		conf.InfluxDB.BatchSize = 5000
+	}
+
+	if conf.InfluxDB.Version == 3 {
+		return connectV3(conf, precision)
+	}
+
 	var auth string
 	if conf.InfluxDB.Token != "" {
 		auth = conf.InfluxDB.Token
@@ -21,48 +51,111 @@ func Connect(conf *config.Configuration) (influx.Client, influxAPI.WriteAPI, err
 		auth = ""
 	}
 
-	var writeDest string
-	if conf.InfluxDB.Bucket != "" {
-		writeDest = conf.InfluxDB.Bucket
-	} else if conf.InfluxDB.Database != "" && conf.InfluxDB.RetentionPolicy != "" {
-		writeDest = fmt.Sprintf("%s/%s", conf.InfluxDB.Database, conf.InfluxDB.RetentionPolicy)
-	} else {
+	writeDest, err := destination(conf.InfluxDB.Bucket, conf.InfluxDB.Database, conf.InfluxDB.RetentionPolicy)
+	if err != nil {
 		return nil, nil, fmt.Errorf("must configure at least one of bucket or database/retention policy")
 	}
 
-	if conf.InfluxDB.FlushInterval == 0 {
-		conf.InfluxDB.FlushInterval = 30
-	}
-
 	options := influx.DefaultOptions().
 		SetFlushInterval(1000 * conf.InfluxDB.FlushInterval).
+		SetBatchSize(conf.InfluxDB.BatchSize).
+		SetPrecision(precision).
+		SetUseGZip(conf.InfluxDB.Gzip).
 		SetTLSConfig(&tls.Config{
 			InsecureSkipVerify: conf.InfluxDB.SkipVerifySsl,
 		})
 	client := influx.NewClientWithOptions(conf.InfluxDB.Address, auth, options)
 
-	writeAPI := client.WriteAPI(conf.InfluxDB.Organization, writeDest)
+	router := newRouter(client.WriteAPI(conf.InfluxDB.Organization, writeDest))
+	for i, route := range conf.InfluxDB.Routes {
+		if len(route.Measurements) == 0 {
+			client.Close()
+			return nil, nil, fmt.Errorf("route %d does not list any measurements", i)
+		}
+		routeDest, err := destination(route.Bucket, route.Database, route.RetentionPolicy)
+		if err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("route %d, %s", i, err)
+		}
+		writeAPI := client.WriteAPI(conf.InfluxDB.Organization, routeDest)
+		for _, measurement := range route.Measurements {
+			router.add(routedMeasurement(conf, measurement), writeAPI)
+		}
+	}
 
-	return client, writeAPI, nil
+	return client, router, nil
+}
+
+// parsePrecision converts the configured timestamp precision to a duration,
+// defaulting to nanoseconds
+func parsePrecision(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "ns":
+		return time.Nanosecond, nil
+	case "us":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	}
+	return 0, fmt.Errorf("unsupported precision %s, must be one of ns, us, ms or s", precision)
 }
 
-// WriteAll writes the Teg data structure into InfluxDB
+// destination returns the write destination for either a bucket (v2) or a
+// database/retention policy pair (v1)
+func destination(bucket, database, retentionPolicy string) (string, error) {
+	if bucket != "" {
+		return bucket, nil
+	} else if database != "" && retentionPolicy != "" {
+		return fmt.Sprintf("%s/%s", database, retentionPolicy), nil
+	}
+	return "", fmt.Errorf("must configure one of bucket or database/retention policy")
+}
+
+// WriteAll writes the Teg data structure into InfluxDB in the native schema,
+// the Powerwall-Dashboard schema or both
 func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg) error {
+	var points []*write.Point
+	if conf.InfluxDB.Schema != SchemaPowerwallDashboard {
+		points = Points(conf, metrics)
+	}
+	if conf.InfluxDB.Schema == SchemaPowerwallDashboard || conf.InfluxDB.Schema == SchemaBoth {
+		points = append(points, DashboardPoints(conf, metrics)...)
+	}
+	for _, p := range points {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// metadata returns the tags describing the gateway and site which are shared
+// by every point
+func metadata(metrics model.Teg) map[string]string {
+	return map[string]string{
+		"gateway_id":        metrics.Status.GatewayID,
+		"firmware_version":  metrics.Status.FirmwareVersion,
+		"firmware_git_hash": metrics.Status.FirmwareGitHash,
+		"sync_type":         metrics.Status.SyncType,
+		"site_name":         metrics.SiteInfo.SiteName,
+		"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
+		"site_country":      metrics.SiteInfo.GridCode.Country,
+		"site_state":        metrics.SiteInfo.GridCode.State,
+		"site_utility":      metrics.SiteInfo.GridCode.Utility,
+	}
+}
+
+// Points flattens the Teg data structure into the points written by WriteAll
+func Points(conf *config.Configuration, metrics model.Teg) []*write.Point {
+	var points []*write.Point
+
+	b := newPointBuilder(conf, metadata(metrics))
 
 	// Meters data
-	p := influx.NewPoint(
+	p := b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_meters",
 		map[string]string{
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"meter_serial":      metrics.MetersStatus.Serial,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
+			"meter_serial": metrics.MetersStatus.Serial,
 		},
 		map[string]interface{}{
 			"measured_frequency":              metrics.SiteInfo.MeasuredFrequency,
@@ -119,22 +212,12 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.Meters.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Overall powerwall info
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
-		map[string]string{
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
-		},
+		nil,
 		map[string]interface{}{
 			"enumerating":                   metrics.Powerwalls.Enumerating,
 			"updating":                      metrics.Powerwalls.Updating,
@@ -151,23 +234,14 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.Powerwalls.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Overall powerwall sync diagnostics
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
 		map[string]string{
-			"diagnostic":        metrics.Powerwalls.Sync.CommissioningDiagnostic.Name,
-			"category":          metrics.Powerwalls.Sync.CommissioningDiagnostic.Category,
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
+			"diagnostic": metrics.Powerwalls.Sync.CommissioningDiagnostic.Name,
+			"category":   metrics.Powerwalls.Sync.CommissioningDiagnostic.Category,
 		},
 		map[string]interface{}{
 			"disruptive": metrics.Powerwalls.Sync.CommissioningDiagnostic.Disruptive,
@@ -175,22 +249,13 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.Powerwalls.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
 		map[string]string{
-			"diagnostic":        metrics.Powerwalls.Sync.UpdateDiagnostic.Name,
-			"category":          metrics.Powerwalls.Sync.UpdateDiagnostic.Category,
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
+			"diagnostic": metrics.Powerwalls.Sync.UpdateDiagnostic.Name,
+			"category":   metrics.Powerwalls.Sync.UpdateDiagnostic.Category,
 		},
 		map[string]interface{}{
 			"disruptive": metrics.Powerwalls.Sync.UpdateDiagnostic.Disruptive,
@@ -198,25 +263,16 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.Powerwalls.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Powerwall diagnostic check results
 	for _, check := range metrics.Powerwalls.Sync.CommissioningDiagnostic.Checks {
-		p = influx.NewPoint(
+		p = b.point(
 			conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
 			map[string]string{
-				"check_name":        check.Name,
-				"diagnostic":        metrics.Powerwalls.Sync.CommissioningDiagnostic.Name,
-				"category":          metrics.Powerwalls.Sync.CommissioningDiagnostic.Category,
-				"gateway_id":        metrics.Status.GatewayID,
-				"firmware_version":  metrics.Status.FirmwareVersion,
-				"firmware_git_hash": metrics.Status.FirmwareGitHash,
-				"sync_type":         metrics.Status.SyncType,
-				"site_name":         metrics.SiteInfo.SiteName,
-				"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-				"site_country":      metrics.SiteInfo.GridCode.Country,
-				"site_state":        metrics.SiteInfo.GridCode.State,
-				"site_utility":      metrics.SiteInfo.GridCode.Utility,
+				"check_name": check.Name,
+				"diagnostic": metrics.Powerwalls.Sync.CommissioningDiagnostic.Name,
+				"category":   metrics.Powerwalls.Sync.CommissioningDiagnostic.Category,
 			},
 			map[string]interface{}{
 				"check_status":     check.Status,
@@ -226,25 +282,16 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 			},
 			metrics.Powerwalls.Timestamp)
 
-		writeAPI.WritePoint(p)
+		points = append(points, p)
 	}
 
 	for _, check := range metrics.Powerwalls.Sync.UpdateDiagnostic.Checks {
-		p = influx.NewPoint(
+		p = b.point(
 			conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
 			map[string]string{
-				"check_name":        check.Name,
-				"diagnostic":        metrics.Powerwalls.Sync.UpdateDiagnostic.Name,
-				"category":          metrics.Powerwalls.Sync.UpdateDiagnostic.Category,
-				"gateway_id":        metrics.Status.GatewayID,
-				"firmware_version":  metrics.Status.FirmwareVersion,
-				"firmware_git_hash": metrics.Status.FirmwareGitHash,
-				"sync_type":         metrics.Status.SyncType,
-				"site_name":         metrics.SiteInfo.SiteName,
-				"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-				"site_country":      metrics.SiteInfo.GridCode.Country,
-				"site_state":        metrics.SiteInfo.GridCode.State,
-				"site_utility":      metrics.SiteInfo.GridCode.Utility,
+				"check_name": check.Name,
+				"diagnostic": metrics.Powerwalls.Sync.UpdateDiagnostic.Name,
+				"category":   metrics.Powerwalls.Sync.UpdateDiagnostic.Category,
 			},
 			map[string]interface{}{
 				"check_status":     check.Status,
@@ -254,23 +301,13 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 			},
 			metrics.Powerwalls.Timestamp)
 
-		writeAPI.WritePoint(p)
+		points = append(points, p)
 	}
 
 	// Overall powerwall usage information
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
-		map[string]string{
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
-		},
+		nil,
 		map[string]interface{}{
 			"battery_target_power":                metrics.SystemStatus.BatteryTargetPower,
 			"battery_target_reactive_power":       metrics.SystemStatus.BatteryTargetReactivePower,
@@ -306,7 +343,7 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.SystemStatus.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Individual powerwall usage information
 	for _, block := range metrics.SystemStatus.BatteryBlocks {
@@ -314,20 +351,11 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		if block.NominalFullPackEnergy > 0 {
 			powerwallChargePercent = float64(block.NominalEnergyRemainingWattHours) / float64(block.NominalFullPackEnergy) * 100.0
 		}
-		p = influx.NewPoint(
+		p = b.point(
 			conf.InfluxDB.MeasurementPrefix+"energy_powerwalls",
 			map[string]string{
 				"powerwall_part_number":   block.PackagePartNumber,
 				"powerwall_serial_number": block.PackageSerialNumber,
-				"gateway_id":              metrics.Status.GatewayID,
-				"firmware_version":        metrics.Status.FirmwareVersion,
-				"firmware_git_hash":       metrics.Status.FirmwareGitHash,
-				"sync_type":               metrics.Status.SyncType,
-				"site_name":               metrics.SiteInfo.SiteName,
-				"site_grid_code":          metrics.SiteInfo.GridCode.GridCode,
-				"site_country":            metrics.SiteInfo.GridCode.Country,
-				"site_state":              metrics.SiteInfo.GridCode.State,
-				"site_utility":            metrics.SiteInfo.GridCode.Utility,
 			},
 			map[string]interface{}{
 				"powerwall_pinv_state":               block.PinvState,
@@ -352,23 +380,13 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 			},
 			metrics.SystemStatus.Timestamp)
 
-		writeAPI.WritePoint(p)
+		points = append(points, p)
 	}
 
 	// Overall site information and configuration
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_configuration",
-		map[string]string{
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
-		},
+		nil,
 		map[string]interface{}{
 			"mode":                          metrics.Operation.RealMode,
 			"backup_reserve_percent":        metrics.Operation.BackupReservePercent,
@@ -385,23 +403,14 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.Operation.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Overall network diagnostics
-	p = influx.NewPoint(
+	p = b.point(
 		conf.InfluxDB.MeasurementPrefix+"energy_network",
 		map[string]string{
-			"diagnostic":        metrics.NetworkConnectionTests.Name,
-			"category":          metrics.NetworkConnectionTests.Category,
-			"gateway_id":        metrics.Status.GatewayID,
-			"firmware_version":  metrics.Status.FirmwareVersion,
-			"firmware_git_hash": metrics.Status.FirmwareGitHash,
-			"sync_type":         metrics.Status.SyncType,
-			"site_name":         metrics.SiteInfo.SiteName,
-			"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-			"site_country":      metrics.SiteInfo.GridCode.Country,
-			"site_state":        metrics.SiteInfo.GridCode.State,
-			"site_utility":      metrics.SiteInfo.GridCode.Utility,
+			"diagnostic": metrics.NetworkConnectionTests.Name,
+			"category":   metrics.NetworkConnectionTests.Category,
 		},
 		map[string]interface{}{
 			"disruptive": metrics.NetworkConnectionTests.Disruptive,
@@ -409,25 +418,16 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 		},
 		metrics.NetworkConnectionTests.Timestamp)
 
-	writeAPI.WritePoint(p)
+	points = append(points, p)
 
 	// Network connectivity tests
 	for _, check := range metrics.NetworkConnectionTests.Checks {
-		p = influx.NewPoint(
+		p = b.point(
 			conf.InfluxDB.MeasurementPrefix+"energy_network",
 			map[string]string{
-				"check_name":        check.Name,
-				"diagnostic":        metrics.NetworkConnectionTests.Name,
-				"category":          metrics.NetworkConnectionTests.Category,
-				"gateway_id":        metrics.Status.GatewayID,
-				"firmware_version":  metrics.Status.FirmwareVersion,
-				"firmware_git_hash": metrics.Status.FirmwareGitHash,
-				"sync_type":         metrics.Status.SyncType,
-				"site_name":         metrics.SiteInfo.SiteName,
-				"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-				"site_country":      metrics.SiteInfo.GridCode.Country,
-				"site_state":        metrics.SiteInfo.GridCode.State,
-				"site_utility":      metrics.SiteInfo.GridCode.Utility,
+				"check_name": check.Name,
+				"diagnostic": metrics.NetworkConnectionTests.Name,
+				"category":   metrics.NetworkConnectionTests.Category,
 			},
 			map[string]interface{}{
 				"check_status":     check.Status,
@@ -436,7 +436,7 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 			},
 			metrics.NetworkConnectionTests.Timestamp)
 
-		writeAPI.WritePoint(p)
+		points = append(points, p)
 	}
 
 	// System status grid fault readings
@@ -449,21 +449,12 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 			default:
 				valueString = decodedAlert.Value.(string)
 			}
-			p = influx.NewPoint(
+			p = b.point(
 				conf.InfluxDB.MeasurementPrefix+"energy_faults",
 				map[string]string{
-					"fault_name":        fault.AlertName,
-					"fault_subname":     decodedAlert.Name,
-					"fault_units":       decodedAlert.Units,
-					"gateway_id":        metrics.Status.GatewayID,
-					"firmware_version":  metrics.Status.FirmwareVersion,
-					"firmware_git_hash": metrics.Status.FirmwareGitHash,
-					"sync_type":         metrics.Status.SyncType,
-					"site_name":         metrics.SiteInfo.SiteName,
-					"site_grid_code":    metrics.SiteInfo.GridCode.GridCode,
-					"site_country":      metrics.SiteInfo.GridCode.Country,
-					"site_state":        metrics.SiteInfo.GridCode.State,
-					"site_utility":      metrics.SiteInfo.GridCode.Utility,
+					"fault_name":    fault.AlertName,
+					"fault_subname": decodedAlert.Name,
+					"fault_units":   decodedAlert.Units,
 				},
 				map[string]interface{}{
 					"grid_fault_ts":                  fault.Timestamp,
@@ -476,9 +467,9 @@ func WriteAll(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics m
 				},
 				metrics.SystemStatus.Timestamp)
 
-			writeAPI.WritePoint(p)
+			points = append(points, p)
 		}
 	}
 
-	return nil
+	return points
 }
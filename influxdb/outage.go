@@ -0,0 +1,53 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/outage"
+)
+
+// WriteOutage writes an outage the snapshot is part of or ended
+func WriteOutage(conf *config.Configuration, writeAPI influxAPI.WriteAPI, metrics model.Teg, event *outage.Event) error {
+	for _, p := range OutagePoints(conf, metrics, event) {
+		writeAPI.WritePoint(p)
+	}
+	return nil
+}
+
+// OutagePoints lays out an outage as an outages point at its start, so each
+// update of an ongoing outage replaces the previous one; powers are in W and
+// energies in Wh
+func OutagePoints(conf *config.Configuration, metrics model.Teg, event *outage.Event) []*write.Point {
+	if event == nil {
+		return nil
+	}
+
+	b := newPointBuilder(conf, metadata(metrics))
+
+	fields := map[string]interface{}{
+		"ongoing":          event.Ongoing(),
+		"duration_seconds": event.Duration(metrics.Meters.Timestamp).Seconds(),
+		"grid_status":      event.GridStatus,
+		"soe_start":        event.SOEStart,
+		"soe_end":          event.SOEEnd,
+		"load_average":     event.LoadAverage,
+		"load_peak":        event.LoadPeak,
+		"load_energy":      event.LoadEnergy,
+		"battery_energy":   event.BatteryEnergy,
+		"solar_energy":     event.SolarEnergy,
+	}
+	if !event.Ongoing() {
+		fields["end_time"] = event.End.UnixNano()
+	}
+
+	p := b.point(
+		conf.InfluxDB.MeasurementPrefix+"outages",
+		nil,
+		fields,
+		event.Start,
+	)
+
+	return []*write.Point{p}
+}
@@ -0,0 +1,100 @@
+package influxdb
+
+import (
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"strings"
+	"sync"
+)
+
+// router is a WriteAPI which dispatches each point to the WriteAPI for its
+// measurement, falling back to the default destination for measurements
+// without a route
+type router struct {
+	defaultAPI influxAPI.WriteAPI
+	routes     map[string]influxAPI.WriteAPI
+	apis       []influxAPI.WriteAPI
+	errCh      chan error
+	errOnce    sync.Once
+}
+
+func newRouter(defaultAPI influxAPI.WriteAPI) *router {
+	return &router{
+		defaultAPI: defaultAPI,
+		routes:     map[string]influxAPI.WriteAPI{},
+		apis:       []influxAPI.WriteAPI{defaultAPI},
+		errCh:      make(chan error),
+	}
+}
+
+// add routes a measurement to writeAPI; the client hands back the same
+// WriteAPI for the same destination so each destination is tracked once
+func (r *router) add(measurement string, writeAPI influxAPI.WriteAPI) {
+	r.routes[measurement] = writeAPI
+	for _, api := range r.apis {
+		if api == writeAPI {
+			return
+		}
+	}
+	r.apis = append(r.apis, writeAPI)
+}
+
+func (r *router) route(measurement string) influxAPI.WriteAPI {
+	if writeAPI, ok := r.routes[measurement]; ok {
+		return writeAPI
+	}
+	return r.defaultAPI
+}
+
+// WriteRecord writes a line protocol record to the destination for its
+// measurement
+func (r *router) WriteRecord(line string) {
+	r.route(lineMeasurement(line)).WriteRecord(line)
+}
+
+// WritePoint writes a point to the destination for its measurement
+func (r *router) WritePoint(point *write.Point) {
+	r.route(point.Name()).WritePoint(point)
+}
+
+// Flush flushes every destination
+func (r *router) Flush() {
+	for _, api := range r.apis {
+		api.Flush()
+	}
+}
+
+// Errors returns a single channel carrying the write errors of every
+// destination
+func (r *router) Errors() <-chan error {
+	r.errOnce.Do(func() {
+		for _, api := range r.apis {
+			go func(errorsCh <-chan error) {
+				for err := range errorsCh {
+					r.errCh <- err
+				}
+			}(api.Errors())
+		}
+	})
+	return r.errCh
+}
+
+// SetWriteFailedCallback sets the callback on every destination
+func (r *router) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+	for _, api := range r.apis {
+		api.SetWriteFailedCallback(cb)
+	}
+}
+
+// lineMeasurement extracts the measurement name from a line protocol record
+func lineMeasurement(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case ',', ' ':
+			return strings.ReplaceAll(strings.ReplaceAll(line[:i], `\,`, ","), `\ `, " ")
+		}
+	}
+	return line
+}
@@ -0,0 +1,75 @@
+package influxdb
+
+import (
+	influx "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"time"
+)
+
+// pointBuilder builds the points of a poll, adding the metadata shared by
+// every point and applying the tag configuration
+type pointBuilder struct {
+	asFields map[string]bool
+	drop     map[string]bool
+	tags     map[string]string
+	fields   map[string]interface{}
+}
+
+// newPointBuilder sorts the metadata into tags, fields or dropped according
+// to the configuration and adds the static tags
+func newPointBuilder(conf *config.Configuration, metadata map[string]string) *pointBuilder {
+	b := &pointBuilder{
+		asFields: map[string]bool{},
+		drop:     map[string]bool{},
+		tags:     map[string]string{},
+		fields:   map[string]interface{}{},
+	}
+	for _, key := range conf.Tags.AsFields {
+		b.asFields[key] = true
+	}
+	for _, key := range conf.Tags.Drop {
+		b.drop[key] = true
+	}
+
+	b.add(metadata)
+	for key, value := range conf.Tags.Static {
+		b.tags[key] = value
+	}
+	return b
+}
+
+// add places tags as tags or fields, or drops them
+func (b *pointBuilder) add(tags map[string]string) {
+	for key, value := range tags {
+		switch {
+		case b.drop[key]:
+		case b.asFields[key]:
+			b.fields[key] = value
+		default:
+			b.tags[key] = value
+		}
+	}
+}
+
+// point builds a point from its own tags and fields plus the metadata; the
+// point's own tags are subject to the same configuration
+func (b *pointBuilder) point(measurement string, tags map[string]string, fields map[string]interface{}, ts time.Time) *write.Point {
+	p := influx.NewPoint(measurement, nil, fields, ts)
+	for key, value := range b.tags {
+		p.AddTag(key, value)
+	}
+	for key, value := range b.fields {
+		p.AddField(key, value)
+	}
+	for key, value := range tags {
+		switch {
+		case b.drop[key]:
+		case b.asFields[key]:
+			p.AddField(key, value)
+		default:
+			p.AddTag(key, value)
+		}
+	}
+	return p.SortTags().SortFields()
+}
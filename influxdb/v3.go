@@ -0,0 +1,297 @@
+package influxdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	http2 "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// v3Precisions maps a timestamp precision to the name used by the v3 API
+var v3Precisions = map[time.Duration]string{
+	time.Nanosecond:  "nanosecond",
+	time.Microsecond: "microsecond",
+	time.Millisecond: "millisecond",
+	time.Second:      "second",
+}
+
+// v3Client owns one v3Writer per database
+type v3Client struct {
+	writers []*v3Writer
+}
+
+// Close flushes and stops every writer
+func (c *v3Client) Close() {
+	for _, w := range c.writers {
+		w.Close()
+	}
+}
+
+// connectV3 returns a client writing to the InfluxDB 3 line protocol endpoint;
+// routes select a database, retention is a property of the database in v3
+func connectV3(conf *config.Configuration, precision time.Duration) (Client, influxAPI.WriteAPI, error) {
+	if conf.InfluxDB.Database == "" {
+		return nil, nil, fmt.Errorf("must configure a database for InfluxDB 3")
+	}
+
+	api := httpClient(conf)
+
+	client := &v3Client{}
+	writers := map[string]*v3Writer{}
+	writer := func(database string) *v3Writer {
+		if w, ok := writers[database]; ok {
+			return w
+		}
+		w := newV3Writer(conf, api, database, precision)
+		writers[database] = w
+		client.writers = append(client.writers, w)
+		return w
+	}
+
+	router := newRouter(writer(conf.InfluxDB.Database))
+	for i, route := range conf.InfluxDB.Routes {
+		if len(route.Measurements) == 0 {
+			client.Close()
+			return nil, nil, fmt.Errorf("route %d does not list any measurements", i)
+		}
+		if route.Database == "" {
+			client.Close()
+			return nil, nil, fmt.Errorf("route %d must configure a database for InfluxDB 3", i)
+		}
+		w := writer(route.Database)
+		for _, measurement := range route.Measurements {
+			router.add(routedMeasurement(conf, measurement), w)
+		}
+	}
+
+	return client, router, nil
+}
+
+// v3Writer is an asynchronous, batching WriteAPI for a single InfluxDB 3
+// database
+type v3Writer struct {
+	client    *http.Client
+	url       string
+	token     string
+	gzip      bool
+	precision time.Duration
+	batchSize int
+
+	mu       sync.Mutex
+	lines    []string
+	callback influxAPI.WriteFailedCallback
+
+	batchCh chan []string
+	flushCh chan chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	errCh   chan error
+	errRead int32
+}
+
+func newV3Writer(conf *config.Configuration, client *http.Client, database string, precision time.Duration) *v3Writer {
+	params := url.Values{
+		"db":             {database},
+		"precision":      {v3Precisions[precision]},
+		"accept_partial": {"true"},
+	}
+	w := &v3Writer{
+		client:    client,
+		url:       strings.TrimSuffix(conf.InfluxDB.Address, "/") + "/api/v3/write_lp?" + params.Encode(),
+		token:     conf.InfluxDB.Token,
+		gzip:      conf.InfluxDB.Gzip,
+		precision: precision,
+		batchSize: int(conf.InfluxDB.BatchSize),
+		batchCh:   make(chan []string),
+		flushCh:   make(chan chan struct{}),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+		errCh:     make(chan error),
+	}
+	go w.run(time.Duration(conf.InfluxDB.FlushInterval) * time.Second)
+	return w
+}
+
+func (w *v3Writer) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.write(w.take())
+		case batch := <-w.batchCh:
+			w.write(batch)
+		case done := <-w.flushCh:
+			w.write(w.take())
+			close(done)
+		case <-w.stopCh:
+			w.write(w.take())
+			close(w.doneCh)
+			return
+		}
+	}
+}
+
+// take empties the buffer and returns its lines
+func (w *v3Writer) take() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	lines := w.lines
+	w.lines = nil
+	return lines
+}
+
+// WriteRecord buffers a line protocol record, sending the batch once it is full
+func (w *v3Writer) WriteRecord(line string) {
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	w.mu.Lock()
+	w.lines = append(w.lines, line)
+	var batch []string
+	if len(w.lines) >= w.batchSize {
+		batch = w.lines
+		w.lines = nil
+	}
+	w.mu.Unlock()
+	if batch != nil {
+		w.batchCh <- batch
+	}
+}
+
+// WritePoint buffers a point, sending the batch once it is full
+func (w *v3Writer) WritePoint(point *write.Point) {
+	w.WriteRecord(write.PointToLineProtocol(point, w.precision))
+}
+
+// Flush writes the buffered lines and waits for the write to finish
+func (w *v3Writer) Flush() {
+	done := make(chan struct{})
+	w.flushCh <- done
+	<-done
+}
+
+// Close flushes the buffered lines and stops the writer
+func (w *v3Writer) Close() {
+	close(w.stopCh)
+	<-w.doneCh
+}
+
+// Errors returns the channel of write errors; partially rejected batches
+// report one error per rejected line
+func (w *v3Writer) Errors() <-chan error {
+	atomic.StoreInt32(&w.errRead, 1)
+	return w.errCh
+}
+
+// SetWriteFailedCallback sets the callback for failed batches; returning true
+// keeps the batch to retry with the next write
+func (w *v3Writer) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = cb
+}
+
+func (w *v3Writer) reportError(err error) {
+	if atomic.LoadInt32(&w.errRead) == 1 {
+		w.errCh <- err
+	}
+}
+
+// write sends a batch to the v3 write endpoint
+func (w *v3Writer) write(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	batch := strings.Join(lines, "")
+
+	var body bytes.Buffer
+	if w.gzip {
+		gz := gzip.NewWriter(&body)
+		gz.Write([]byte(batch))
+		gz.Close()
+	} else {
+		body.WriteString(batch)
+	}
+
+	req, err := http.NewRequest("POST", w.url, &body)
+	if err != nil {
+		w.reportError(err)
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		w.failed(lines, &http2.Error{Err: err})
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		w.reportError(err)
+		return
+	}
+
+	if resp.StatusCode/100 == 2 {
+		return
+	}
+
+	partial := struct {
+		Error string `json:"error"`
+		Data  []struct {
+			OriginalLine string `json:"original_line"`
+			LineNumber   int    `json:"line_number"`
+			ErrorMessage string `json:"error_message"`
+		} `json:"data"`
+	}{}
+	if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(respBody, &partial) == nil && len(partial.Data) > 0 {
+		// The remaining lines were written, only the rejected ones are reported
+		for _, line := range partial.Data {
+			w.reportError(fmt.Errorf("%s; line %d rejected, %s; raw line %s", partial.Error, line.LineNumber, line.ErrorMessage, line.OriginalLine))
+		}
+		return
+	}
+
+	w.failed(lines, &http2.Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(respBody),
+		Header:     resp.Header,
+	})
+}
+
+// failed reports a batch which was not written and keeps it for a retry if the
+// write failed callback asks for one
+func (w *v3Writer) failed(lines []string, httpErr *http2.Error) {
+	w.mu.Lock()
+	cb := w.callback
+	w.mu.Unlock()
+
+	if cb != nil && cb(strings.Join(lines, ""), *httpErr, 0) {
+		w.mu.Lock()
+		w.lines = append(lines, w.lines...)
+		w.mu.Unlock()
+		return
+	}
+
+	w.reportError(fmt.Errorf("error when writing batch of %d lines, %s", len(lines), httpErr))
+}
@@ -0,0 +1,195 @@
+package influxdb
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	http2 "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+)
+
+// v3Server is a stand-in for the InfluxDB 3 write endpoint which records each
+// request and answers with the next queued response
+type v3Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []*http.Request
+	bodies    []string
+	responses []func(w http.ResponseWriter)
+}
+
+func newV3Server(t *testing.T) *v3Server {
+	s := &v3Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		var err error
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, gzErr := gzip.NewReader(r.Body)
+			if gzErr != nil {
+				t.Errorf("error when reading gzip body, %s", gzErr)
+				return
+			}
+			body, err = ioutil.ReadAll(gz)
+		} else {
+			body, err = ioutil.ReadAll(r.Body)
+		}
+		if err != nil {
+			t.Errorf("error when reading body, %s", err)
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, string(body))
+		var respond func(w http.ResponseWriter)
+		if len(s.responses) > 0 {
+			respond = s.responses[0]
+			s.responses = s.responses[1:]
+		}
+		s.mu.Unlock()
+
+		if respond == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		respond(w)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *v3Server) respond(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+func (s *v3Server) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func newTestV3Writer(t *testing.T, s *v3Server, gzip bool) (*v3Writer, <-chan error) {
+	conf := &config.Configuration{}
+	conf.InfluxDB.Address = s.URL + "/"
+	conf.InfluxDB.Token = "secret"
+	conf.InfluxDB.Gzip = gzip
+	conf.InfluxDB.BatchSize = 100
+	conf.InfluxDB.FlushInterval = 3600
+	w := newV3Writer(conf, httpClient(conf), "energy", time.Second)
+	t.Cleanup(w.Close)
+
+	errs := make(chan error, 10)
+	writeErrs := w.Errors()
+	go func() {
+		for err := range writeErrs {
+			errs <- err
+		}
+	}()
+	return w, errs
+}
+
+func TestV3WriterWritesBatch(t *testing.T) {
+	s := newV3Server(t)
+	w, errs := newTestV3Writer(t, s, true)
+
+	w.WriteRecord("meters,site=home power=1 1")
+	w.WriteRecord("meters,site=home power=2 2\n")
+	w.Flush()
+
+	bodies := s.received()
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request but got %d", len(bodies))
+	}
+	if bodies[0] != "meters,site=home power=1 1\nmeters,site=home power=2 2\n" {
+		t.Errorf("unexpected body %q", bodies[0])
+	}
+	r := s.requests[0]
+	if r.URL.Path != "/api/v3/write_lp" {
+		t.Errorf("unexpected path %s", r.URL.Path)
+	}
+	query := r.URL.Query()
+	if query.Get("db") != "energy" || query.Get("precision") != "second" || query.Get("accept_partial") != "true" {
+		t.Errorf("unexpected query %s", r.URL.RawQuery)
+	}
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("unexpected error %s", err)
+	default:
+	}
+}
+
+func TestV3WriterReportsRejectedLines(t *testing.T) {
+	s := newV3Server(t)
+	s.respond(http.StatusBadRequest, `{"error": "partial write of line protocol occurred", "data": [
+		{"original_line": "meters power=\"x\" 2", "line_number": 2, "error_message": "invalid column type"}]}`)
+	w, errs := newTestV3Writer(t, s, false)
+
+	w.WriteRecord("meters power=1 1")
+	w.WriteRecord(`meters power="x" 2`)
+	w.Flush()
+
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "line 2 rejected, invalid column type") {
+			t.Errorf("unexpected error %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an error for the rejected line")
+	}
+
+	// The accepted lines were written, so nothing is kept for a retry
+	w.Flush()
+	if bodies := s.received(); len(bodies) != 1 {
+		t.Errorf("expected 1 request but got %d", len(bodies))
+	}
+}
+
+func TestV3WriterRetriesFailedBatch(t *testing.T) {
+	s := newV3Server(t)
+	s.respond(http.StatusServiceUnavailable, "unavailable")
+	w, errs := newTestV3Writer(t, s, false)
+
+	var calls int
+	w.SetWriteFailedCallback(func(batch string, err http2.Error, retryAttempts uint) bool {
+		calls++
+		if err.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("unexpected status code %d", err.StatusCode)
+		}
+		return true
+	})
+
+	w.WriteRecord("meters power=1 1")
+	w.Flush()
+	w.WriteRecord("meters power=2 2")
+	w.Flush()
+
+	bodies := s.received()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests but got %d", len(bodies))
+	}
+	if bodies[1] != "meters power=1 1\nmeters power=2 2\n" {
+		t.Errorf("expected the failed batch to be retried but got %q", bodies[1])
+	}
+	if calls != 1 {
+		t.Errorf("expected the callback to be called once but got %d", calls)
+	}
+	select {
+	case err := <-errs:
+		t.Errorf("unexpected error %s", err)
+	default:
+	}
+}
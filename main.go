@@ -3,9 +3,26 @@ package main
 import (
 	"flag"
 	"fmt"
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/iwvelando/tesla-energy-stats-collector/alert"
+	"github.com/iwvelando/tesla-energy-stats-collector/archive"
+	"github.com/iwvelando/tesla-energy-stats-collector/battery"
+	"github.com/iwvelando/tesla-energy-stats-collector/billing"
 	"github.com/iwvelando/tesla-energy-stats-collector/config"
 	"github.com/iwvelando/tesla-energy-stats-collector/connect"
+	"github.com/iwvelando/tesla-energy-stats-collector/demand"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/events"
 	"github.com/iwvelando/tesla-energy-stats-collector/influxdb"
+	"github.com/iwvelando/tesla-energy-stats-collector/modbus"
+	"github.com/iwvelando/tesla-energy-stats-collector/otlp"
+	"github.com/iwvelando/tesla-energy-stats-collector/outage"
+	"github.com/iwvelando/tesla-energy-stats-collector/postgres"
+	"github.com/iwvelando/tesla-energy-stats-collector/pvoutput"
+	"github.com/iwvelando/tesla-energy-stats-collector/sqlite"
+	"github.com/iwvelando/tesla-energy-stats-collector/stream"
+	"github.com/iwvelando/tesla-energy-stats-collector/tariff"
+	"github.com/iwvelando/tesla-energy-stats-collector/webhook"
 	log "github.com/sirupsen/logrus"
 	"os"
 	"os/signal"
@@ -33,6 +50,63 @@ func main() {
 		}).Fatal("failed to parse configuration")
 	}
 
+	switch flags.Arg(0) {
+	case "":
+	case "setup":
+		err = influxdb.Bootstrap(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "influxdb.Bootstrap",
+				"error": err,
+			}).Fatal("failed to bootstrap InfluxDB")
+		}
+		log.WithFields(log.Fields{
+			"op": "influxdb.Bootstrap",
+		}).Info("bootstrapped InfluxDB")
+		return
+	case "query":
+		err = runQuery(conf, flags.Args()[1:])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "sqlite.Query",
+				"error": err,
+			}).Fatal("failed to query SQLite store")
+		}
+		return
+	case "statement":
+		err = runStatement(conf, flags.Args()[1:])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "billing.Print",
+				"error": err,
+			}).Fatal("failed to print billing statement")
+		}
+		return
+	case "outages":
+		err = runOutages(conf, flags.Args()[1:])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "outage.Print",
+				"error": err,
+			}).Fatal("failed to print outages")
+		}
+		return
+	default:
+		log.WithFields(log.Fields{
+			"op": "main",
+		}).Fatal(fmt.Sprintf("unknown command %s", flags.Arg(0)))
+	}
+
+	if conf.InfluxDB.Bootstrap.OnStartup {
+		err = influxdb.Bootstrap(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "influxdb.Bootstrap",
+				"error": err,
+			}).Fatal("failed to bootstrap InfluxDB")
+		}
+	}
+
 	tesla, refreshTime, err := connect.Auth(conf)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -42,28 +116,284 @@ func main() {
 	}
 	defer tesla.CloseIdleConnections()
 
-	influxClient, writeAPI, err := influxdb.Connect(conf)
-	if err != nil {
-		log.WithFields(log.Fields{
-			"op":    "influxdb.Connect",
-			"error": err,
-		}).Fatal("failed to authenticate to InfluxDB")
+	var outputs []influxAPI.WriteAPI
+
+	if conf.InfluxDB.Address != "" {
+		influxClient, influxWriteAPI, err := influxdb.Connect(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "influxdb.Connect",
+				"error": err,
+			}).Fatal("failed to authenticate to InfluxDB")
+		}
+		defer influxClient.Close()
+		outputs = append(outputs, influxWriteAPI)
+	}
+
+	if conf.SQLite.Path != "" {
+		store, err := sqlite.Open(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "sqlite.Open",
+				"error": err,
+			}).Fatal("failed to open SQLite store")
+		}
+		defer store.Close()
+		outputs = append(outputs, store)
+	}
+
+	if conf.Archive.Directory != "" {
+		archiveWriter, err := archive.Open(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "archive.Open",
+				"error": err,
+			}).Fatal("failed to open archive")
+		}
+		defer archiveWriter.Close()
+		outputs = append(outputs, archiveWriter)
+	}
+
+	writeAPI := influxdb.NewFanOut(outputs...)
+	if conf.ChangeFilter.Heartbeat > 0 {
+		writeAPI = influxdb.NewChangeFilter(conf, writeAPI)
+	}
+	if conf.Aggregation.Window > 0 {
+		writeAPI = influxdb.NewAggregator(conf, writeAPI)
 	}
-	defer influxClient.Close()
 	defer writeAPI.Flush()
 
 	errorsCh := writeAPI.Errors()
 
-	// Monitor InfluxDB write errors
+	// Monitor InfluxDB, SQLite and archive write errors
 	go func() {
 		for err := range errorsCh {
 			log.WithFields(log.Fields{
 				"op":    "influxdb.WriteAll",
 				"error": err,
-			}).Error("encountered error on writing to InfluxDB")
+			}).Error("encountered error on writing points")
 		}
 	}()
 
+	var accountant *energy.Accountant
+	if conf.Energy.StateFile != "" {
+		accountant, err = energy.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "energy.New",
+				"error": err,
+			}).Fatal("failed to load energy accounting state")
+		}
+	}
+
+	var tariffEngine *tariff.Engine
+	if conf.Tariff.StateFile != "" {
+		if accountant == nil {
+			log.WithFields(log.Fields{
+				"op": "tariff.New",
+			}).Fatal("the tariff prices the energy totals, so energy.stateFile must be set too")
+		}
+		tariffEngine, err = tariff.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "tariff.New",
+				"error": err,
+			}).Fatal("failed to configure tariff")
+		}
+	}
+
+	var ledger *billing.Ledger
+	if conf.Billing.StateFile != "" {
+		if accountant == nil {
+			log.WithFields(log.Fields{
+				"op": "billing.New",
+			}).Fatal("billing accumulates the energy totals, so energy.stateFile must be set too")
+		}
+		ledger, err = billing.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "billing.New",
+				"error": err,
+			}).Fatal("failed to configure billing")
+		}
+	}
+
+	var demandTracker *demand.Tracker
+	if conf.Demand.Window > 0 {
+		demandTracker, err = demand.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "demand.New",
+				"error": err,
+			}).Fatal("failed to configure demand tracking")
+		}
+	}
+
+	var outageDetector *outage.Detector
+	if conf.Outages.StateFile != "" {
+		outageDetector, err = outage.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "outage.New",
+				"error": err,
+			}).Fatal("failed to load outage history")
+		}
+	}
+
+	var eventLog *events.Log
+	if conf.Events.StateFile != "" {
+		eventLog, err = events.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "events.New",
+				"error": err,
+			}).Fatal("failed to load last known settings")
+		}
+	}
+
+	var batteryTracker *battery.Tracker
+	if conf.Battery.StateFile != "" {
+		batteryTracker, err = battery.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "battery.New",
+				"error": err,
+			}).Fatal("failed to load battery health state")
+		}
+	}
+
+	var pgSink *postgres.Sink
+	if conf.PostgreSQL.ConnectionString != "" {
+		pgSink, err = postgres.Connect(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "postgres.Connect",
+				"error": err,
+			}).Fatal("failed to connect to PostgreSQL")
+		}
+		defer pgSink.Close()
+	}
+
+	var otlpExporter *otlp.Exporter
+	if conf.OTLP.Endpoint != "" {
+		otlpExporter, err = otlp.Connect(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "otlp.Connect",
+				"error": err,
+			}).Fatal("failed to connect to OTLP collector")
+		}
+		defer otlpExporter.Close()
+	}
+
+	var snapshotStream *stream.Stream
+	if len(conf.Stream.Kafka.Brokers) > 0 || conf.Stream.NATS.URL != "" {
+		snapshotStream, err = stream.Connect(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "stream.Connect",
+				"error": err,
+			}).Fatal("failed to connect to snapshot stream")
+		}
+		defer snapshotStream.Close()
+
+		streamErrorsCh := snapshotStream.Errors()
+		go func() {
+			for err := range streamErrorsCh {
+				log.WithFields(log.Fields{
+					"op":    "stream.Publish",
+					"error": err,
+				}).Error("encountered error on publishing snapshots")
+			}
+		}()
+	}
+
+	var webhooks *webhook.Dispatcher
+	if len(conf.Webhooks) > 0 {
+		webhooks, err = webhook.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "webhook.New",
+				"error": err,
+			}).Fatal("failed to configure webhooks")
+		}
+
+		webhookErrorsCh := webhooks.Errors()
+		go func() {
+			for err := range webhookErrorsCh {
+				log.WithFields(log.Fields{
+					"op":    "webhook.Send",
+					"error": err,
+				}).Error("encountered error on sending webhook")
+			}
+		}()
+	}
+
+	var pvUploader *pvoutput.Uploader
+	if conf.PVOutput.APIKey != "" {
+		pvUploader, err = pvoutput.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "pvoutput.New",
+				"error": err,
+			}).Fatal("failed to configure PVOutput uploader")
+		}
+		defer pvUploader.Close()
+
+		pvErrorsCh := pvUploader.Errors()
+		go func() {
+			for err := range pvErrorsCh {
+				log.WithFields(log.Fields{
+					"op":    "pvoutput.Upload",
+					"error": err,
+				}).Error("encountered error on uploading to PVOutput")
+			}
+		}()
+	}
+
+	var modbusServer *modbus.Server
+	if conf.Modbus.Listen != "" {
+		modbusServer, err = modbus.Listen(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "modbus.Listen",
+				"error": err,
+			}).Fatal("failed to start Modbus TCP server")
+		}
+		defer modbusServer.Close()
+
+		modbusErrorsCh := modbusServer.Errors()
+		go func() {
+			for err := range modbusErrorsCh {
+				log.WithFields(log.Fields{
+					"op":    "modbus.Serve",
+					"error": err,
+				}).Error("encountered error on serving Modbus TCP")
+			}
+		}()
+	}
+
+	var alerts *alert.Engine
+	if len(conf.Alerting.Rules) > 0 {
+		alerts, err = alert.New(conf)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "alert.New",
+				"error": err,
+			}).Fatal("failed to configure alerting")
+		}
+
+		alertErrorsCh := alerts.Errors()
+		go func() {
+			for err := range alertErrorsCh {
+				log.WithFields(log.Fields{
+					"op":    "alert.Notify",
+					"error": err,
+				}).Error("encountered error on sending alert notification")
+			}
+		}()
+	}
+
 	// Look for SIGTERM or SIGINT
 	cancelCh := make(chan os.Signal, 1)
 	signal.Notify(cancelCh, syscall.SIGTERM, syscall.SIGINT)
@@ -100,6 +430,157 @@ func main() {
 				}
 			} else {
 				influxdb.WriteAll(conf, writeAPI, metrics)
+				if accountant != nil {
+					totals, err := accountant.Update(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "energy.Update",
+							"error": err,
+						}).Error("encountered error on accounting energy")
+					}
+					for _, t := range totals {
+						for meter, reason := range t.Resets {
+							log.WithFields(log.Fields{
+								"op":     "energy.Update",
+								"meter":  meter,
+								"reason": reason,
+							}).Warn("rebaselined energy counters")
+						}
+					}
+					influxdb.WriteEnergy(conf, writeAPI, metrics, totals)
+					if tariffEngine != nil {
+						costs, err := tariffEngine.Update(totals)
+						if err != nil {
+							log.WithFields(log.Fields{
+								"op":    "tariff.Update",
+								"error": err,
+							}).Error("encountered error on pricing energy")
+						}
+						influxdb.WriteCosts(conf, writeAPI, metrics, costs)
+					}
+					if ledger != nil {
+						err = ledger.Update(totals)
+						if err != nil {
+							log.WithFields(log.Fields{
+								"op":    "billing.Update",
+								"error": err,
+							}).Error("encountered error on billing energy")
+						}
+					}
+				}
+				if demandTracker != nil {
+					d, err := demandTracker.Update(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "demand.Update",
+							"error": err,
+						}).Error("encountered error on tracking demand")
+					}
+					influxdb.WriteDemand(conf, writeAPI, metrics, d)
+				}
+				if outageDetector != nil {
+					event, err := outageDetector.Update(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "outage.Update",
+							"error": err,
+						}).Error("encountered error on recording outage")
+					}
+					if event != nil && event.Ongoing() && event.Samples == 1 {
+						log.WithFields(log.Fields{
+							"op":          "outage.Update",
+							"grid_status": event.GridStatus,
+						}).Warn("grid outage started")
+					} else if event != nil && !event.Ongoing() {
+						log.WithFields(log.Fields{
+							"op":       "outage.Update",
+							"duration": event.Duration(event.End),
+						}).Info("grid outage ended")
+					}
+					influxdb.WriteOutage(conf, writeAPI, metrics, event)
+				}
+				if eventLog != nil {
+					changes, err := eventLog.Update(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "events.Update",
+							"error": err,
+						}).Error("encountered error on saving last known settings")
+					}
+					for _, e := range changes {
+						log.WithFields(log.Fields{
+							"op":    "events.Update",
+							"event": e.Name,
+						}).Info(e.Text)
+					}
+					influxdb.WriteEvents(conf, writeAPI, metrics, changes)
+				}
+				if batteryTracker != nil {
+					health, err := batteryTracker.Update(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "battery.Update",
+							"error": err,
+						}).Error("encountered error on tracking battery health")
+					}
+					influxdb.WriteBatteryHealth(conf, writeAPI, metrics, health)
+				}
+				if pgSink != nil {
+					err = pgSink.WriteAll(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "postgres.WriteAll",
+							"error": err,
+						}).Error("encountered error on writing to PostgreSQL")
+					}
+				}
+				if otlpExporter != nil {
+					err = otlpExporter.Export(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "otlp.Export",
+							"error": err,
+						}).Error("encountered error on exporting to OTLP collector")
+					}
+				}
+				if snapshotStream != nil {
+					err = snapshotStream.Publish(metrics)
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "stream.Publish",
+							"error": err,
+						}).Error("encountered error on publishing snapshot")
+					}
+				}
+				if webhooks != nil {
+					webhooks.Send(metrics)
+				}
+				if pvUploader != nil {
+					pvUploader.Record(metrics)
+				}
+				if modbusServer != nil {
+					modbusServer.Update(metrics)
+				}
+				if alerts != nil {
+					changes, err := alerts.Update(metrics, time.Now())
+					if err != nil {
+						log.WithFields(log.Fields{
+							"op":    "alert.Update",
+							"error": err,
+						}).Error("encountered error on evaluating alert rules")
+					}
+					logAlerts(changes)
+				}
+			}
+			if alerts != nil {
+				changes, err := alerts.Tick(time.Now())
+				if err != nil {
+					log.WithFields(log.Fields{
+						"op":    "alert.Tick",
+						"error": err,
+					}).Error("encountered error on evaluating alert rules")
+				}
+				logAlerts(changes)
 			}
 
 			timeRemaining := conf.Polling.Interval*time.Second - time.Since(pollStartTime)
@@ -112,7 +593,108 @@ func main() {
 	sig := <-cancelCh
 	log.WithFields(log.Fields{
 		"op": "main",
-	}).Info(fmt.Sprintf("caught signal %v, flushing data to InfluxDB", sig))
+	}).Info(fmt.Sprintf("caught signal %v, flushing data", sig))
 	writeAPI.Flush()
+	if accountant != nil {
+		err = accountant.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "energy.Close",
+				"error": err,
+			}).Error("encountered error on saving energy accounting state")
+		}
+	}
+	if tariffEngine != nil {
+		err = tariffEngine.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "tariff.Close",
+				"error": err,
+			}).Error("encountered error on saving tariff state")
+		}
+	}
+	if ledger != nil {
+		err = ledger.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "billing.Close",
+				"error": err,
+			}).Error("encountered error on saving billing state")
+		}
+	}
+	if demandTracker != nil {
+		err = demandTracker.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "demand.Close",
+				"error": err,
+			}).Error("encountered error on saving demand state")
+		}
+	}
+	if outageDetector != nil {
+		err = outageDetector.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "outage.Close",
+				"error": err,
+			}).Error("encountered error on saving outage history")
+		}
+	}
+	if eventLog != nil {
+		err = eventLog.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "events.Close",
+				"error": err,
+			}).Error("encountered error on saving last known settings")
+		}
+	}
+	if batteryTracker != nil {
+		err = batteryTracker.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "battery.Close",
+				"error": err,
+			}).Error("encountered error on saving battery health state")
+		}
+	}
+	if alerts != nil {
+		err = alerts.Close()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "alert.Close",
+				"error": err,
+			}).Error("encountered error on saving alert state")
+		}
+	}
+	if webhooks != nil {
+		webhooks.Wait()
+	}
+	if pgSink != nil {
+		err = pgSink.Flush()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"op":    "postgres.Flush",
+				"error": err,
+			}).Error("encountered error on writing to PostgreSQL")
+		}
+	}
+
+}
 
+// logAlerts logs the alerts which changed state
+func logAlerts(changes []alert.Alert) {
+	for _, a := range changes {
+		fields := log.Fields{
+			"op":    "alert.Update",
+			"alert": a.ID(),
+			"state": a.State,
+		}
+		switch a.State {
+		case alert.Firing:
+			log.WithFields(fields).Warn(a.Summary)
+		default:
+			log.WithFields(fields).Info(a.Summary)
+		}
+	}
 }
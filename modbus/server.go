@@ -0,0 +1,205 @@
+// Package modbus serves the latest snapshot over Modbus TCP as SunSpec
+// register blocks for Modbus-only energy management systems.
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Function codes served; everything else is answered with an exception
+const (
+	readHoldingRegisters = 0x03
+	readInputRegisters   = 0x04
+)
+
+// Exception codes
+const (
+	illegalFunction    = 0x01
+	illegalDataAddress = 0x02
+	illegalDataValue   = 0x03
+	targetNoResponse   = 0x0B
+)
+
+// maxQuantity is the most registers a single read may request
+const maxQuantity = 125
+
+// idleTimeout closes connections which have not sent a request in a while
+const idleTimeout = 5 * time.Minute
+
+// Server answers register reads from the SunSpec map of the latest snapshot
+type Server struct {
+	listener    net.Listener
+	baseAddress uint16
+	unitID      uint8
+
+	mu   sync.RWMutex
+	regs []uint16
+
+	wg    sync.WaitGroup
+	conns sync.Map
+
+	errCh   chan error
+	errRead int32
+}
+
+// Listen starts serving Modbus TCP on the configured address; until the first
+// poll the map holds only its fixed registers (the SunSpec marker, model
+// headers, manufacturer, model and unit ID) and every measurement reads as zero
+func Listen(conf *config.Configuration) (*Server, error) {
+	if conf.Modbus.UnitID == 0 {
+		conf.Modbus.UnitID = 1
+	}
+	if conf.Modbus.BaseAddress == 0 {
+		conf.Modbus.BaseAddress = 40000
+	}
+
+	listener, err := net.Listen("tcp", conf.Modbus.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("error when listening on %s, %s", conf.Modbus.Listen, err)
+	}
+
+	s := &Server{
+		listener:    listener,
+		baseAddress: conf.Modbus.BaseAddress,
+		unitID:      conf.Modbus.UnitID,
+		regs:        registers(model.Teg{}, conf.Modbus.UnitID),
+		errCh:       make(chan error),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Update replaces the register map with the snapshot
+func (s *Server) Update(metrics model.Teg) {
+	regs := registers(metrics, s.unitID)
+	s.mu.Lock()
+	s.regs = regs
+	s.mu.Unlock()
+}
+
+// Close stops listening and closes open connections
+func (s *Server) Close() {
+	s.listener.Close()
+	s.conns.Range(func(key, value interface{}) bool {
+		key.(net.Conn).Close()
+		return true
+	})
+	s.wg.Wait()
+}
+
+// Errors returns the channel of server errors
+func (s *Server) Errors() <-chan error {
+	atomic.StoreInt32(&s.errRead, 1)
+	return s.errCh
+}
+
+func (s *Server) reportError(err error) {
+	if atomic.LoadInt32(&s.errRead) == 1 {
+		s.errCh <- err
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return
+		}
+		s.conns.Store(conn, struct{}{})
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// handle answers requests on a connection until it is closed; each request
+// is a 7 byte MBAP header followed by the PDU
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer s.conns.Delete(conn)
+	defer conn.Close()
+
+	header := make([]byte, 7)
+	for {
+		conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+
+		length := binary.BigEndian.Uint16(header[4:6])
+		if binary.BigEndian.Uint16(header[2:4]) != 0 || length < 2 || length > 254 {
+			s.reportError(fmt.Errorf("invalid request header from %s", conn.RemoteAddr()))
+			return
+		}
+		pdu := make([]byte, length-1)
+		_, err = io.ReadFull(conn, pdu)
+		if err != nil {
+			return
+		}
+
+		reply := s.respond(header[6], pdu)
+		resp := make([]byte, 7, 7+len(reply))
+		copy(resp, header[:4])
+		binary.BigEndian.PutUint16(resp[4:6], uint16(len(reply)+1))
+		resp[6] = header[6]
+		resp = append(resp, reply...)
+
+		_, err = conn.Write(resp)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// respond builds the response PDU to a request PDU for a unit; like a gateway
+// with a single device behind it, requests for other units are answered with
+// an exception, except for 0 and 255 which Modbus TCP clients use to address
+// the server itself
+func (s *Server) respond(unit uint8, pdu []byte) []byte {
+	function := pdu[0]
+	if unit != s.unitID && unit != 0 && unit != 255 {
+		return []byte{function | 0x80, targetNoResponse}
+	}
+	if function != readHoldingRegisters && function != readInputRegisters {
+		return []byte{function | 0x80, illegalFunction}
+	}
+	if len(pdu) != 5 {
+		return []byte{function | 0x80, illegalDataValue}
+	}
+
+	address := binary.BigEndian.Uint16(pdu[1:3])
+	quantity := binary.BigEndian.Uint16(pdu[3:5])
+	if quantity < 1 || quantity > maxQuantity {
+		return []byte{function | 0x80, illegalDataValue}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if address < s.baseAddress || int(address-s.baseAddress)+int(quantity) > len(s.regs) {
+		return []byte{function | 0x80, illegalDataAddress}
+	}
+
+	reply := make([]byte, 2+2*int(quantity))
+	reply[0] = function
+	reply[1] = byte(2 * quantity)
+	for i, v := range s.regs[address-s.baseAddress : int(address-s.baseAddress)+int(quantity)] {
+		binary.BigEndian.PutUint16(reply[2+2*i:], v)
+	}
+	return reply
+}
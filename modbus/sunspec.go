@@ -0,0 +1,283 @@
+package modbus
+
+import (
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+)
+
+// SunSpec model IDs and lengths of the blocks in the register map
+const (
+	modelCommon      = 1
+	lenCommon        = 66
+	modelMeter       = 201
+	lenMeter         = 105
+	modelInverter    = 103
+	lenInverter      = 50
+	modelStorage     = 124
+	lenStorage       = 24
+	modelEnd         = 0xFFFF
+	sunSpecIDHigh    = 0x5375 // "Su"
+	sunSpecIDLow     = 0x6e53 // "nS"
+	unimplemented16  = 0x8000
+	unimplementedU16 = 0xFFFF
+)
+
+// Fixed scale factors; values are stored as value / 10^sf. Power scale
+// factors are picked from the values each poll, see powerSF.
+const (
+	sfCurrent = -2
+	sfVoltage = -1
+	sfFreq    = -2
+	sfEnergy  = 0
+	sfPercent = -1
+)
+
+// Storage charge states of model 124
+const (
+	chaStOff         = 1
+	chaStDischarging = 3
+	chaStCharging    = 4
+	chaStHolding     = 6
+)
+
+// Inverter operating states of model 103
+const (
+	stSleeping = 2
+	stMPPT     = 4
+)
+
+// block writes the registers of one SunSpec model
+type block []uint16
+
+func (b block) u16(offset int, v uint16) {
+	b[offset] = v
+}
+
+func (b block) sf(offset int, sf int) {
+	b[offset] = uint16(int16(sf))
+}
+
+// powerSF picks the smallest scale factor from 0 at which every value fits
+// within limit, so power is in whole watts until a larger site needs tens or
+// hundreds of watts per count
+func powerSF(limit float64, values ...float64) int {
+	largest := 0.0
+	for _, v := range values {
+		if a := math.Abs(v); a > largest {
+			largest = a
+		}
+	}
+	sf := 0
+	for largest/math.Pow10(sf) > limit && sf < 10 {
+		sf++
+	}
+	return sf
+}
+
+// scaled stores a value as an int16 at the given scale factor, clamped to the
+// representable range
+func (b block) scaled(offset int, v float64, sf int) {
+	scaled := math.Round(v / math.Pow10(sf))
+	if math.IsNaN(scaled) {
+		b[offset] = unimplemented16
+		return
+	}
+	scaled = math.Max(math.Min(scaled, math.MaxInt16), -math.MaxInt16)
+	b[offset] = uint16(int16(scaled))
+}
+
+// scaledU stores a non-negative value as a uint16 at the given scale factor
+func (b block) scaledU(offset int, v float64, sf int) {
+	scaled := math.Round(v / math.Pow10(sf))
+	if math.IsNaN(scaled) || scaled < 0 {
+		b[offset] = unimplementedU16
+		return
+	}
+	b[offset] = uint16(math.Min(scaled, math.MaxUint16-1))
+}
+
+// acc32 stores an accumulator as a big-endian uint32 over two registers
+func (b block) acc32(offset int, v float64, sf int) {
+	scaled := uint32(math.Max(math.Min(math.Round(v/math.Pow10(sf)), math.MaxUint32), 0))
+	b[offset] = uint16(scaled >> 16)
+	b[offset+1] = uint16(scaled)
+}
+
+// str stores a string as two bytes per register, null padded
+func (b block) str(offset, registers int, s string) {
+	for i := 0; i < registers; i++ {
+		var hi, lo byte
+		if 2*i < len(s) {
+			hi = s[2*i]
+		}
+		if 2*i+1 < len(s) {
+			lo = s[2*i+1]
+		}
+		b[offset+i] = uint16(hi)<<8 | uint16(lo)
+	}
+}
+
+// fill marks every register of a block as unimplemented
+func (b block) fill(v uint16) {
+	for i := range b {
+		b[i] = v
+	}
+}
+
+// newBlock appends a model header and returns the model's registers
+func newBlock(regs *[]uint16, id, length int) block {
+	*regs = append(*regs, uint16(id), uint16(length))
+	start := len(*regs)
+	*regs = append(*regs, make([]uint16, length)...)
+	return block((*regs)[start:])
+}
+
+// registers lays out the snapshot as SunSpec models: common, a meter each for
+// the site, load and battery, an inverter for solar and storage
+func registers(metrics model.Teg, unitID uint8) []uint16 {
+	regs := []uint16{sunSpecIDHigh, sunSpecIDLow}
+
+	common := newBlock(&regs, modelCommon, lenCommon)
+	common.str(0, 16, "Tesla")
+	common.str(16, 16, "Powerwall")
+	common.str(32, 8, metrics.Status.DeviceType)
+	common.str(40, 8, metrics.Status.FirmwareVersion)
+	common.str(48, 16, metrics.Status.GatewayID)
+	common.u16(64, uint16(unitID))
+	common.u16(65, unimplemented16)
+
+	for _, meter := range []model.TegMetersAggregate{metrics.Meters.Site, metrics.Meters.Load, metrics.Meters.Battery} {
+		meterBlock(newBlock(&regs, modelMeter, lenMeter), meter)
+	}
+
+	inverterBlock(newBlock(&regs, modelInverter, lenInverter), metrics.Meters.Solar)
+	storageBlock(newBlock(&regs, modelStorage, lenStorage), metrics)
+
+	regs = append(regs, modelEnd, 0)
+	return regs
+}
+
+// meterBlock fills a model 201 meter; power keeps the gateway's sign, so the
+// site is positive when importing and the battery when discharging
+func meterBlock(b block, meter model.TegMetersAggregate) {
+	b.fill(unimplemented16)
+
+	b.scaled(0, meter.InstantTotalCurrent, sfCurrent)
+	b.scaled(1, meter.IACurrent, sfCurrent)
+	b.scaled(2, meter.IBCurrent, sfCurrent)
+	b.scaled(3, meter.ICCurrent, sfCurrent)
+	b.sf(4, sfCurrent)
+	b.scaled(5, meter.InstantAverageVoltage, sfVoltage)
+	b.sf(13, sfVoltage)
+	b.scaled(14, meter.Frequency, sfFreq)
+	b.sf(15, sfFreq)
+	sf := powerSF(math.MaxInt16, meter.InstantPowerWatts)
+	b.scaled(16, meter.InstantPowerWatts, sf)
+	b.sf(20, sf)
+	sf = powerSF(math.MaxInt16, meter.InstantApparentPowerWatts)
+	b.scaled(21, meter.InstantApparentPowerWatts, sf)
+	b.sf(25, sf)
+	sf = powerSF(math.MaxInt16, meter.InstantReactivePowerWatts)
+	b.scaled(26, meter.InstantReactivePowerWatts, sf)
+	b.sf(30, sf)
+
+	// Energy accumulators are zero rather than 0x8000 when not implemented
+	for offset := 36; offset < 52; offset++ {
+		b[offset] = 0
+	}
+	b.acc32(36, meter.EnergyExportedWatts, sfEnergy)
+	b.acc32(44, meter.EnergyImportedWatts, sfEnergy)
+	b.sf(52, sfEnergy)
+	for offset := 53; offset < 104; offset++ {
+		b[offset] = 0
+	}
+	b.sf(69, 0)
+	b.sf(102, 0)
+	b.u16(103, 0)
+	b.u16(104, 0)
+}
+
+// inverterBlock fills a model 103 inverter from the solar meter
+func inverterBlock(b block, solar model.TegMetersAggregate) {
+	b.fill(unimplemented16)
+
+	b.scaledU(0, math.Abs(solar.InstantTotalCurrent), sfCurrent)
+	b.scaledU(1, math.Abs(solar.IACurrent), sfCurrent)
+	b.scaledU(2, math.Abs(solar.IBCurrent), sfCurrent)
+	b.scaledU(3, math.Abs(solar.ICCurrent), sfCurrent)
+	b.sf(4, sfCurrent)
+	b.u16(5, unimplementedU16)
+	b.u16(6, unimplementedU16)
+	b.u16(7, unimplementedU16)
+	b.scaledU(8, solar.InstantAverageVoltage, sfVoltage)
+	b.u16(9, unimplementedU16)
+	b.u16(10, unimplementedU16)
+	b.sf(11, sfVoltage)
+	sf := powerSF(math.MaxInt16, solar.InstantPowerWatts)
+	b.scaled(12, solar.InstantPowerWatts, sf)
+	b.sf(13, sf)
+	b.scaledU(14, solar.Frequency, sfFreq)
+	b.sf(15, sfFreq)
+	sf = powerSF(math.MaxInt16, solar.InstantApparentPowerWatts)
+	b.scaled(16, solar.InstantApparentPowerWatts, sf)
+	b.sf(17, sf)
+	sf = powerSF(math.MaxInt16, solar.InstantReactivePowerWatts)
+	b.scaled(18, solar.InstantReactivePowerWatts, sf)
+	b.sf(19, sf)
+	b.acc32(22, solar.EnergyExportedWatts, sfEnergy)
+	b.sf(24, sfEnergy)
+	b.u16(25, unimplementedU16)
+	b.u16(27, unimplementedU16)
+
+	state := uint16(stSleeping)
+	if solar.InstantPowerWatts > 0 {
+		state = stMPPT
+	}
+	b.u16(36, state)
+	b.u16(37, unimplementedU16)
+	for offset := 38; offset < 50; offset++ {
+		b[offset] = 0
+	}
+}
+
+// storageBlock fills a model 124 storage block with the state of energy,
+// backup reserve and battery power as a percentage of the maximum charge
+// power, positive when discharging
+func storageBlock(b block, metrics model.Teg) {
+	b.fill(unimplementedU16)
+
+	maxCharge := float64(metrics.SystemStatus.MaxChargePowerWatts)
+	battery := metrics.Meters.Battery.InstantPowerWatts
+
+	sf := powerSF(math.MaxUint16-1, maxCharge)
+	b.scaledU(0, maxCharge, sf)
+	b.scaledU(5, metrics.Operation.BackupReservePercent, sfPercent)
+	b.scaledU(6, metrics.SystemStateOfEnergy.Percentage, sfPercent)
+
+	state := uint16(chaStHolding)
+	switch {
+	case metrics.SystemStatus.AvailableBlocks == 0:
+		state = chaStOff
+	case battery > 0:
+		state = chaStDischarging
+	case battery < 0:
+		state = chaStCharging
+	}
+	b.u16(9, state)
+
+	rate := math.NaN()
+	if maxCharge > 0 {
+		rate = battery / maxCharge * 100
+	}
+	b.scaled(10, rate, sfPercent)
+	b.scaled(11, -rate, sfPercent)
+
+	b.sf(16, sf)
+	b.sf(17, 0)
+	b.sf(18, 0)
+	b.sf(19, sfPercent)
+	b.sf(20, sfPercent)
+	b.sf(21, 0)
+	b.sf(22, 0)
+	b.sf(23, sfPercent)
+}
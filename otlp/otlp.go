@@ -0,0 +1,284 @@
+// Package otlp exports polled data as OpenTelemetry metrics over OTLP/gRPC or
+// OTLP/HTTP.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const scopeName = "github.com/iwvelando/tesla-energy-stats-collector"
+
+// Exporter pushes each snapshot to an OTLP collector
+type Exporter struct {
+	conf       *config.Configuration
+	conn       *grpc.ClientConn
+	grpcClient colmetricspb.MetricsServiceClient
+	httpClient *http.Client
+	url        string
+}
+
+// Connect prepares the exporter for the configured protocol; gRPC connections
+// are established lazily on the first export
+func Connect(conf *config.Configuration) (*Exporter, error) {
+	if conf.OTLP.Timeout == 0 {
+		conf.OTLP.Timeout = 10 * time.Second
+	}
+
+	e := &Exporter{
+		conf: conf,
+	}
+
+	switch conf.OTLP.Protocol {
+	case "", "grpc":
+		creds := credentials.NewTLS(&tls.Config{InsecureSkipVerify: conf.OTLP.SkipVerifySsl})
+		if conf.OTLP.Insecure {
+			creds = insecure.NewCredentials()
+		}
+		conn, err := grpc.NewClient(conf.OTLP.Endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, err
+		}
+		e.conn = conn
+		e.grpcClient = colmetricspb.NewMetricsServiceClient(conn)
+	case "http":
+		e.url = conf.OTLP.Endpoint
+		if !strings.HasSuffix(e.url, "/v1/metrics") {
+			e.url = strings.TrimSuffix(e.url, "/") + "/v1/metrics"
+		}
+		e.httpClient = &http.Client{
+			Timeout: conf.OTLP.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: conf.OTLP.SkipVerifySsl},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protocol %s, must be one of grpc or http", conf.OTLP.Protocol)
+	}
+
+	return e, nil
+}
+
+// Close releases the connection to the collector
+func (e *Exporter) Close() {
+	if e.conn != nil {
+		e.conn.Close()
+	}
+	if e.httpClient != nil {
+		e.httpClient.CloseIdleConnections()
+	}
+}
+
+// Export converts the Teg data structure into OTLP metrics and pushes them
+func (e *Exporter) Export(metrics model.Teg) error {
+	req := e.request(metrics)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.conf.OTLP.Timeout)
+	defer cancel()
+
+	if e.grpcClient != nil {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(e.conf.OTLP.Headers))
+		resp, err := e.grpcClient.Export(ctx, req)
+		if err != nil {
+			return err
+		}
+		if rejected := resp.GetPartialSuccess().GetRejectedDataPoints(); rejected > 0 {
+			return fmt.Errorf("collector rejected %d data points, %s", rejected, resp.GetPartialSuccess().GetErrorMessage())
+		}
+		return nil
+	}
+
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.conf.OTLP.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("expected 2xx HTTP status code but got %d; raw body %s", resp.StatusCode, respBody)
+	}
+
+	exportResp := &colmetricspb.ExportMetricsServiceResponse{}
+	if proto.Unmarshal(respBody, exportResp) == nil {
+		if rejected := exportResp.GetPartialSuccess().GetRejectedDataPoints(); rejected > 0 {
+			return fmt.Errorf("collector rejected %d data points, %s", rejected, exportResp.GetPartialSuccess().GetErrorMessage())
+		}
+	}
+
+	return nil
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func point(ts time.Time, value float64, attrs ...*commonpb.KeyValue) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:   attrs,
+		TimeUnixNano: uint64(ts.UnixNano()),
+		Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+func gauge(name, unit, description string, points ...*metricspb.NumberDataPoint) *metricspb.Metric {
+	return &metricspb.Metric{
+		Name:        name,
+		Unit:        unit,
+		Description: description,
+		Data:        &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: points}},
+	}
+}
+
+// counter builds a cumulative monotonic sum for a lifetime register; the
+// start time is left unset because the register started counting long before
+// the collector, so backends do not read a restart as a burst of energy
+func counter(name, unit, description string, points ...*metricspb.NumberDataPoint) *metricspb.Metric {
+	return &metricspb.Metric{
+		Name:        name,
+		Unit:        unit,
+		Description: description,
+		Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+			DataPoints:             points,
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}},
+	}
+}
+
+// request builds the OTLP export request for a snapshot
+func (e *Exporter) request(metrics model.Teg) *colmetricspb.ExportMetricsServiceRequest {
+	site := e.conf.OTLP.Site
+	if site == "" {
+		site = metrics.SiteInfo.SiteName
+	}
+
+	meters := []struct {
+		name  string
+		meter model.TegMetersAggregate
+	}{
+		{"site", metrics.Meters.Site},
+		{"battery", metrics.Meters.Battery},
+		{"load", metrics.Meters.Load},
+		{"solar", metrics.Meters.Solar},
+	}
+
+	ts := metrics.Meters.Timestamp
+	var power, reactivePower, apparentPower, voltage, current, frequency, imported, exported []*metricspb.NumberDataPoint
+	for _, m := range meters {
+		attr := stringAttr("meter", m.name)
+		power = append(power, point(ts, m.meter.InstantPowerWatts, attr))
+		reactivePower = append(reactivePower, point(ts, m.meter.InstantReactivePowerWatts, attr))
+		apparentPower = append(apparentPower, point(ts, m.meter.InstantApparentPowerWatts, attr))
+		voltage = append(voltage, point(ts, m.meter.InstantAverageVoltage, attr))
+		current = append(current, point(ts, m.meter.InstantTotalCurrent, attr))
+		frequency = append(frequency, point(ts, m.meter.Frequency, attr))
+		imported = append(imported, point(ts, m.meter.EnergyImportedWatts, attr))
+		exported = append(exported, point(ts, m.meter.EnergyExportedWatts, attr))
+	}
+
+	ts = metrics.SystemStatus.Timestamp
+	var blockSoe, blockRemaining, blockFullPack, blockPower, blockCharged, blockDischarged []*metricspb.NumberDataPoint
+	for _, block := range metrics.SystemStatus.BatteryBlocks {
+		attr := stringAttr("powerwall_serial_number", block.PackageSerialNumber)
+		soe := 0.0
+		if block.NominalFullPackEnergy > 0 {
+			soe = float64(block.NominalEnergyRemainingWattHours) / float64(block.NominalFullPackEnergy) * 100.0
+		}
+		blockSoe = append(blockSoe, point(ts, soe, attr))
+		blockRemaining = append(blockRemaining, point(ts, float64(block.NominalEnergyRemainingWattHours), attr))
+		blockFullPack = append(blockFullPack, point(ts, float64(block.NominalFullPackEnergy), attr))
+		blockPower = append(blockPower, point(ts, block.POut, attr))
+		blockCharged = append(blockCharged, point(ts, float64(block.EnergyCharged), attr))
+		blockDischarged = append(blockDischarged, point(ts, float64(block.EnergyDischarged), attr))
+	}
+
+	gridConnected := 0.0
+	if metrics.SystemGridStatus.GridStatus == "SystemGridConnected" {
+		gridConnected = 1.0
+	}
+
+	otlpMetrics := []*metricspb.Metric{
+		gauge("tesla.energy.meter.power", "W", "Instantaneous real power per meter; site positive on import, battery positive on discharge", power...),
+		gauge("tesla.energy.meter.reactive_power", "VAR", "Instantaneous reactive power per meter", reactivePower...),
+		gauge("tesla.energy.meter.apparent_power", "VA", "Instantaneous apparent power per meter", apparentPower...),
+		gauge("tesla.energy.meter.voltage", "V", "Instantaneous average voltage per meter", voltage...),
+		gauge("tesla.energy.meter.current", "A", "Instantaneous total current per meter", current...),
+		gauge("tesla.energy.meter.frequency", "Hz", "Frequency per meter", frequency...),
+		counter("tesla.energy.meter.energy_imported", "Wh", "Lifetime energy imported per meter", imported...),
+		counter("tesla.energy.meter.energy_exported", "Wh", "Lifetime energy exported per meter", exported...),
+		gauge("tesla.energy.battery.state_of_energy", "%", "System state of energy",
+			point(metrics.SystemStateOfEnergy.Timestamp, metrics.SystemStateOfEnergy.Percentage)),
+		gauge("tesla.energy.battery.energy_remaining", "Wh", "Nominal energy remaining across all batteries",
+			point(ts, float64(metrics.SystemStatus.NominalEnergyRemainingWattHours))),
+		gauge("tesla.energy.battery.full_pack_energy", "Wh", "Nominal full pack energy across all batteries",
+			point(ts, float64(metrics.SystemStatus.NominalFullPackEnergyWattHours))),
+		gauge("tesla.energy.grid.connected", "1", "1 when the site is connected to the grid, 0 when islanded",
+			point(metrics.SystemGridStatus.Timestamp, gridConnected)),
+		gauge("tesla.energy.backup_reserve", "%", "Configured backup reserve",
+			point(metrics.Operation.Timestamp, metrics.Operation.BackupReservePercent)),
+	}
+	if len(metrics.SystemStatus.BatteryBlocks) > 0 {
+		otlpMetrics = append(otlpMetrics,
+			gauge("tesla.energy.powerwall.state_of_energy", "%", "State of energy per Powerwall", blockSoe...),
+			gauge("tesla.energy.powerwall.energy_remaining", "Wh", "Nominal energy remaining per Powerwall", blockRemaining...),
+			gauge("tesla.energy.powerwall.full_pack_energy", "Wh", "Nominal full pack energy per Powerwall", blockFullPack...),
+			gauge("tesla.energy.powerwall.power", "W", "Real power output per Powerwall", blockPower...),
+			counter("tesla.energy.powerwall.energy_charged", "Wh", "Lifetime energy charged per Powerwall", blockCharged...),
+			counter("tesla.energy.powerwall.energy_discharged", "Wh", "Lifetime energy discharged per Powerwall", blockDischarged...),
+		)
+	}
+
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{
+					stringAttr("service.name", "tesla-energy-stats-collector"),
+					stringAttr("gateway_id", metrics.Status.GatewayID),
+					stringAttr("firmware_version", metrics.Status.FirmwareVersion),
+					stringAttr("site", site),
+				},
+			},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: scopeName},
+				Metrics: otlpMetrics,
+			}},
+		}},
+	}
+}
@@ -0,0 +1,178 @@
+package otlp
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func snapshot() model.Teg {
+	var metrics model.Teg
+	ts := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	metrics.SiteInfo.SiteName = "home"
+	metrics.Meters.Timestamp = ts
+	metrics.Meters.Solar.InstantPowerWatts = 4200
+	metrics.Meters.Site.EnergyImportedWatts = 12345678
+	metrics.SystemStatus.Timestamp = ts
+	metrics.SystemStatus.BatteryBlocks = []model.TegBatteryBlock{{
+		PackageSerialNumber:             "TG1",
+		NominalEnergyRemainingWattHours: 6750,
+		NominalFullPackEnergy:           13500,
+		EnergyCharged:                   5000000,
+	}}
+	return metrics
+}
+
+// find returns the metric of a request by name
+func find(t *testing.T, req *colmetricspb.ExportMetricsServiceRequest, name string) *metricspb.Metric {
+	for _, m := range req.GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics() {
+		if m.GetName() == name {
+			return m
+		}
+	}
+	t.Fatalf("metric %s not exported", name)
+	return nil
+}
+
+func checkRequest(t *testing.T, req *colmetricspb.ExportMetricsServiceRequest) {
+	power := find(t, req, "tesla.energy.meter.power")
+	for _, p := range power.GetGauge().GetDataPoints() {
+		if p.GetAttributes()[0].GetValue().GetStringValue() == "solar" && p.GetAsDouble() != 4200 {
+			t.Errorf("expected solar power 4200 but got %f", p.GetAsDouble())
+		}
+	}
+
+	// Lifetime registers are cumulative monotonic sums without a start time,
+	// so a restart is not read as a burst
+	for _, name := range []string{"tesla.energy.meter.energy_imported", "tesla.energy.powerwall.energy_charged"} {
+		sum := find(t, req, name).GetSum()
+		if !sum.GetIsMonotonic() || sum.GetAggregationTemporality() != metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+			t.Fatalf("expected %s to be a cumulative monotonic sum", name)
+		}
+		if start := sum.GetDataPoints()[0].GetStartTimeUnixNano(); start != 0 {
+			t.Errorf("expected %s to have no start time but got %d", name, start)
+		}
+	}
+	for _, p := range find(t, req, "tesla.energy.meter.energy_imported").GetSum().GetDataPoints() {
+		if p.GetAttributes()[0].GetValue().GetStringValue() == "site" && p.GetAsDouble() != 12345678 {
+			t.Errorf("expected site energy imported 12345678 but got %f", p.GetAsDouble())
+		}
+	}
+
+	soe := find(t, req, "tesla.energy.powerwall.state_of_energy").GetGauge().GetDataPoints()[0]
+	if soe.GetAsDouble() != 50 {
+		t.Errorf("expected Powerwall state of energy 50 but got %f", soe.GetAsDouble())
+	}
+}
+
+func TestExportHTTP(t *testing.T) {
+	var got *colmetricspb.ExportMetricsServiceRequest
+	var path, auth string
+	reject := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		got = &colmetricspb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, got); err != nil {
+			t.Errorf("error when decoding request, %s", err)
+		}
+		resp := &colmetricspb.ExportMetricsServiceResponse{}
+		if reject {
+			resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{RejectedDataPoints: 2, ErrorMessage: "bad points"}
+		}
+		out, _ := proto.Marshal(resp)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(out)
+	}))
+	defer srv.Close()
+
+	conf := &config.Configuration{}
+	conf.OTLP.Endpoint = srv.URL
+	conf.OTLP.Protocol = "http"
+	conf.OTLP.Headers = map[string]string{"Authorization": "Bearer token"}
+	e, err := Connect(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	err = e.Export(snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v1/metrics" {
+		t.Errorf("unexpected path %s", path)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("unexpected authorization %q", auth)
+	}
+	checkRequest(t, got)
+
+	reject = true
+	err = e.Export(snapshot())
+	if err == nil || !strings.Contains(err.Error(), "rejected 2 data points") {
+		t.Errorf("expected a partial success error but got %v", err)
+	}
+}
+
+// collector is a stand-in OTLP/gRPC metrics service
+type collector struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	requests chan *colmetricspb.ExportMetricsServiceRequest
+	headers  chan metadata.MD
+}
+
+func (c *collector) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.headers <- md
+	c.requests <- req
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func TestExportGRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collector{
+		requests: make(chan *colmetricspb.ExportMetricsServiceRequest, 1),
+		headers:  make(chan metadata.MD, 1),
+	}
+	srv := grpc.NewServer()
+	colmetricspb.RegisterMetricsServiceServer(srv, c)
+	go srv.Serve(ln)
+	defer srv.Stop()
+
+	conf := &config.Configuration{}
+	conf.OTLP.Endpoint = ln.Addr().String()
+	conf.OTLP.Insecure = true
+	conf.OTLP.Headers = map[string]string{"x-api-key": "secret"}
+	e, err := Connect(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	err = e.Export(snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md := <-c.headers; len(md.Get("x-api-key")) == 0 || md.Get("x-api-key")[0] != "secret" {
+		t.Errorf("expected x-api-key metadata but got %v", md)
+	}
+	checkRequest(t, <-c.requests)
+}
@@ -0,0 +1,203 @@
+// Package outage detects grid outages from the grid status and records each
+// one as an event with how the site got through it.
+package outage
+
+import (
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"math"
+	"sync"
+	"time"
+)
+
+// gridConnected is the grid status while the site is on the grid
+const gridConnected = "SystemGridConnected"
+
+// maxGap is the longest time between polls that is integrated; power is
+// not assumed to have held over longer gaps
+const maxGap = 5 * time.Minute
+
+// Event is an outage; End is zero while it is ongoing. Powers are in W and
+// energies in Wh.
+type Event struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	GridStatus string    `json:"grid_status"`
+	SOEStart   float64   `json:"soe_start"`
+	SOEEnd     float64   `json:"soe_end"`
+	Samples    int       `json:"samples"`
+
+	LoadAverage   float64 `json:"load_average"`
+	LoadPeak      float64 `json:"load_peak"`
+	LoadEnergy    float64 `json:"load_energy"`
+	BatteryEnergy float64 `json:"battery_energy"`
+	SolarEnergy   float64 `json:"solar_energy"`
+
+	// Hours is the time energy was integrated over
+	Hours float64 `json:"hours"`
+
+	// The previous poll, integrated up to the next
+	Last        time.Time `json:"last"`
+	LastLoad    float64   `json:"last_load"`
+	LastBattery float64   `json:"last_battery"`
+	LastSolar   float64   `json:"last_solar"`
+}
+
+// Ongoing reports whether the outage has not ended yet
+func (e Event) Ongoing() bool {
+	return e.End.IsZero()
+}
+
+// Duration is how long the outage lasted, or has lasted so far at now
+func (e Event) Duration(now time.Time) time.Duration {
+	if e.Ongoing() {
+		return now.Sub(e.Start)
+	}
+	return e.End.Sub(e.Start)
+}
+
+// State is what the Detector persists and the outages command reads
+type State struct {
+	Ongoing *Event  `json:"ongoing"`
+	History []Event `json:"history"`
+}
+
+// Events returns the past outages and the ongoing one, oldest first
+func (s State) Events() []Event {
+	events := append([]Event(nil), s.History...)
+	if s.Ongoing != nil {
+		events = append(events, *s.Ongoing)
+	}
+	return events
+}
+
+// Detector follows the grid status from poll to poll
+type Detector struct {
+	path string
+
+	// mu guards the ongoing outage and the history, which Close saves while
+	// the poll loop may still be extending or ending the outage
+	mu     sync.Mutex
+	closed bool
+	state  State
+}
+
+// New loads the detector's state, if any was saved, so an outage which was
+// ongoing when the collector stopped is carried on
+func New(conf *config.Configuration) (*Detector, error) {
+	d := &Detector{path: conf.Outages.StateFile}
+	var err error
+	d.state, err = Load(conf)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Load reads the saved state
+func Load(conf *config.Configuration) (State, error) {
+	var s State
+	err := energy.LoadState(conf.Outages.StateFile, &s)
+	return s, err
+}
+
+// Close saves the ongoing outage and the history; grid statuses polled
+// afterwards no longer start or end an outage
+func (d *Detector) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	return d.save()
+}
+
+func (d *Detector) save() error {
+	return energy.SaveState(d.path, d.state)
+}
+
+// Islanded reports whether a snapshot shows the site off the grid, by the
+// grid status or, failing that, the island state
+func Islanded(metrics model.Teg) bool {
+	status := metrics.SystemGridStatus.GridStatus
+	if status == "" {
+		status = metrics.SystemStatus.SystemIslandState
+	}
+	return status != "" && status != gridConnected
+}
+
+// integrate adds the energy from the previous poll up to ts at the powers of
+// the previous poll
+func (e *Event) integrate(ts time.Time) {
+	dt := ts.Sub(e.Last)
+	if dt <= 0 || dt > maxGap {
+		return
+	}
+	hours := dt.Hours()
+	e.Hours += hours
+	e.LoadEnergy += e.LastLoad * hours
+	e.BatteryEnergy += e.LastBattery * hours
+	e.SolarEnergy += e.LastSolar * hours
+	if e.Hours > 0 {
+		e.LoadAverage = e.LoadEnergy / e.Hours
+	}
+}
+
+// sample records a poll taken during the outage
+func (e *Event) sample(metrics model.Teg) {
+	load := math.Max(metrics.Meters.Load.InstantPowerWatts, 0)
+	e.Samples++
+	e.LoadPeak = math.Max(e.LoadPeak, load)
+	if e.Hours == 0 {
+		e.LoadAverage = load
+	}
+	e.SOEEnd = metrics.SystemStateOfEnergy.Percentage
+	e.Last = metrics.Meters.Timestamp
+	e.LastLoad = load
+	e.LastBattery = math.Max(metrics.Meters.Battery.InstantPowerWatts, 0)
+	e.LastSolar = math.Max(metrics.Meters.Solar.InstantPowerWatts, 0)
+}
+
+// Update follows the grid status and returns the outage the snapshot is part
+// of or ended, if any; the state is saved whenever an outage starts or ends
+func (d *Detector) Update(metrics model.Teg) (*Event, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, nil
+	}
+
+	ts := metrics.Meters.Timestamp
+	islanded := Islanded(metrics)
+	e := d.state.Ongoing
+
+	switch {
+	case islanded && e == nil:
+		e = &Event{
+			Start:      ts,
+			GridStatus: metrics.SystemGridStatus.GridStatus,
+			SOEStart:   metrics.SystemStateOfEnergy.Percentage,
+		}
+		e.sample(metrics)
+		d.state.Ongoing = e
+	case islanded:
+		if !ts.After(e.Last) {
+			return e, nil
+		}
+		e.integrate(ts)
+		e.sample(metrics)
+		return e, nil
+	case e != nil:
+		e.integrate(ts)
+		e.End = ts
+		e.SOEEnd = metrics.SystemStateOfEnergy.Percentage
+		d.state.History = append(d.state.History, *e)
+		d.state.Ongoing = nil
+	default:
+		return nil, nil
+	}
+
+	return e, d.save()
+}
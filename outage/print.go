@@ -0,0 +1,73 @@
+package outage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// eventJSON is how an event is printed as JSON
+type eventJSON struct {
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	GridStatus      string     `json:"grid_status"`
+	SOEStart        float64    `json:"soe_start"`
+	SOEEnd          float64    `json:"soe_end"`
+	LoadAverage     float64    `json:"load_average_w"`
+	LoadPeak        float64    `json:"load_peak_w"`
+	LoadEnergy      float64    `json:"load_energy_wh"`
+	BatteryEnergy   float64    `json:"battery_energy_wh"`
+	SolarEnergy     float64    `json:"solar_energy_wh"`
+}
+
+// Print writes the events as a table or JSON, newest first
+func Print(w io.Writer, events []Event, now time.Time, format string) error {
+	switch format {
+	case "json":
+		out := make([]eventJSON, 0, len(events))
+		for i := len(events) - 1; i >= 0; i-- {
+			e := events[i]
+			j := eventJSON{
+				Start:           e.Start,
+				DurationSeconds: e.Duration(now).Seconds(),
+				GridStatus:      e.GridStatus,
+				SOEStart:        e.SOEStart,
+				SOEEnd:          e.SOEEnd,
+				LoadAverage:     e.LoadAverage,
+				LoadPeak:        e.LoadPeak,
+				LoadEnergy:      e.LoadEnergy,
+				BatteryEnergy:   e.BatteryEnergy,
+				SolarEnergy:     e.SolarEnergy,
+			}
+			if !e.Ongoing() {
+				end := e.End
+				j.End = &end
+			}
+			out = append(out, j)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case "table":
+	default:
+		return fmt.Errorf("unsupported format %s, must be one of table or json", format)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "start\tend\tduration\tgrid status\tSOE %\tload avg W\tload peak W\tload kWh\tbattery kWh\tsolar kWh")
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		end := "ongoing"
+		if !e.Ongoing() {
+			end = e.End.Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f -> %.1f\t%.0f\t%.0f\t%.2f\t%.2f\t%.2f\n",
+			e.Start.Local().Format(time.RFC3339), end, e.Duration(now).Round(time.Second), e.GridStatus,
+			e.SOEStart, e.SOEEnd, e.LoadAverage, e.LoadPeak,
+			e.LoadEnergy/1000, e.BatteryEnergy/1000, e.SolarEnergy/1000)
+	}
+	return tw.Flush()
+}
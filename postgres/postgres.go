@@ -0,0 +1,296 @@
+// Package postgres writes polled data into relational tables in PostgreSQL,
+// using TimescaleDB hypertables when the extension is available.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"sync"
+	"time"
+)
+
+// columns lists the columns copied into each time series table
+var columns = map[string][]string{
+	"meters": {
+		"time", "gateway_id", "meter", "last_comm_time", "instant_power",
+		"instant_reactive_power", "instant_apparent_power", "frequency",
+		"energy_exported", "energy_imported", "instant_average_voltage",
+		"instant_average_current", "instant_total_current",
+	},
+	"powerwalls": {
+		"time", "gateway_id", "charge_percent", "battery_target_power",
+		"battery_target_reactive_power", "nominal_full_pack_energy",
+		"nominal_energy_remaining", "expected_energy_remaining", "max_charge_power",
+		"max_discharge_power", "instantaneous_max_charge_power",
+		"instantaneous_max_discharge_power", "grid_services_power",
+		"solar_real_power_limit", "system_island_state", "available_blocks",
+		"blocks_controlled", "updating", "enumerating", "checking_if_offgrid",
+		"grid_qualifying", "bubble_shedding",
+	},
+	"battery_blocks": {
+		"time", "gateway_id", "battery_id", "pinv_state", "pinv_grid_state",
+		"op_seq_state", "nominal_energy_remaining", "nominal_full_pack_energy",
+		"p_out", "q_out", "v_out", "f_out", "i_out", "energy_charged",
+		"energy_discharged", "off_grid", "vf_mode", "wobble_detected",
+		"charge_power_clamped", "backup_ready", "disabled_reasons",
+	},
+	"configuration": {
+		"time", "gateway_id", "mode", "backup_reserve_percent",
+		"freq_shift_load_shed_soe", "freq_shift_load_shed_delta_f", "net_meter_mode",
+		"sitemaster_status", "sitemaster_running", "sitemaster_connected_to_tesla",
+		"sitemaster_power_supply_mode", "grid_status", "grid_services_active",
+	},
+	"network_checks": {
+		"time", "gateway_id", "diagnostic", "check_name", "status", "start_time",
+		"end_time",
+	},
+	"faults": {
+		"time", "gateway_id", "fault_timestamp", "alert_name", "is_fault",
+		"alert_raw", "ecu_type", "ecu_part_number", "ecu_serial_number",
+		"decoded_alert",
+	},
+}
+
+// Sink buffers polled rows and copies them into PostgreSQL in batches
+type Sink struct {
+	mu        sync.Mutex
+	pool      *pgxpool.Pool
+	batchSize int
+	sites     map[string]int32
+	devices   map[string]int32
+	rows      map[string][][]interface{}
+	polls     int
+}
+
+// Connect connects to PostgreSQL and applies any pending schema migrations
+func Connect(conf *config.Configuration) (*Sink, error) {
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, conf.PostgreSQL.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Migrate(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	if conf.PostgreSQL.BatchSize == 0 {
+		conf.PostgreSQL.BatchSize = 1
+	}
+
+	return &Sink{
+		pool:      pool,
+		batchSize: int(conf.PostgreSQL.BatchSize),
+		sites:     map[string]int32{},
+		devices:   map[string]int32{},
+		rows:      map[string][][]interface{}{},
+	}, nil
+}
+
+// Close releases the connection pool; call Flush first to write buffered rows
+func (s *Sink) Close() {
+	s.pool.Close()
+}
+
+// siteID returns the id of the site, creating it if needed
+func (s *Sink) siteID(ctx context.Context, siteInfo model.TegSiteInfo) (int32, error) {
+	key := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", siteInfo.SiteName, siteInfo.GridCode.GridCode, siteInfo.GridCode.Country,
+		siteInfo.GridCode.State, siteInfo.GridCode.Utility, siteInfo.Timezone)
+	if id, ok := s.sites[key]; ok {
+		return id, nil
+	}
+
+	var id int32
+	err := s.pool.QueryRow(ctx, `INSERT INTO sites (site_name, grid_code, country, state, utility, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (site_name, grid_code, country, state, utility) DO UPDATE SET timezone = EXCLUDED.timezone
+		RETURNING id`,
+		siteInfo.SiteName, siteInfo.GridCode.GridCode, siteInfo.GridCode.Country,
+		siteInfo.GridCode.State, siteInfo.GridCode.Utility, siteInfo.Timezone).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error when upserting site %s, %s", siteInfo.SiteName, err)
+	}
+	s.sites[key] = id
+
+	return id, nil
+}
+
+// deviceID returns the id of the device, creating it or refreshing its
+// metadata when that changed
+func (s *Sink) deviceID(ctx context.Context, siteID int32, kind, serial, partNumber, firmwareVersion, firmwareGitHash string) (int32, error) {
+	key := fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s", siteID, kind, serial, partNumber, firmwareVersion, firmwareGitHash)
+	if id, ok := s.devices[key]; ok {
+		return id, nil
+	}
+
+	var id int32
+	err := s.pool.QueryRow(ctx, `INSERT INTO devices (site_id, kind, serial_number, part_number, firmware_version, firmware_git_hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (kind, serial_number) DO UPDATE SET
+			site_id = EXCLUDED.site_id,
+			part_number = EXCLUDED.part_number,
+			firmware_version = EXCLUDED.firmware_version,
+			firmware_git_hash = EXCLUDED.firmware_git_hash,
+			last_seen = now()
+		RETURNING id`,
+		siteID, kind, serial, partNumber, firmwareVersion, firmwareGitHash).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error when upserting %s %s, %s", kind, serial, err)
+	}
+	s.devices[key] = id
+
+	return id, nil
+}
+
+// nullTime maps unset times to NULL
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// WriteAll buffers the Teg data structure as rows and copies them into
+// PostgreSQL once the configured number of polls is buffered
+func (s *Sink) WriteAll(metrics model.Teg) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx := context.Background()
+
+	siteID, err := s.siteID(ctx, metrics.SiteInfo)
+	if err != nil {
+		return err
+	}
+	gatewayID, err := s.deviceID(ctx, siteID, "gateway", metrics.Status.GatewayID, metrics.Status.DeviceType, metrics.Status.FirmwareVersion, metrics.Status.FirmwareGitHash)
+	if err != nil {
+		return err
+	}
+
+	// The poll's rows are only buffered once every device is known, so a
+	// failed lookup does not leave half a poll behind
+	rows := map[string][][]interface{}{}
+
+	meters := map[string]model.TegMetersAggregate{
+		"site":    metrics.Meters.Site,
+		"battery": metrics.Meters.Battery,
+		"load":    metrics.Meters.Load,
+		"solar":   metrics.Meters.Solar,
+	}
+	for name, meter := range meters {
+		rows["meters"] = append(rows["meters"], []interface{}{
+			metrics.Meters.Timestamp, gatewayID, name, nullTime(meter.LastCommunicationTime),
+			meter.InstantPowerWatts, meter.InstantReactivePowerWatts, meter.InstantApparentPowerWatts,
+			meter.Frequency, meter.EnergyExportedWatts, meter.EnergyImportedWatts,
+			meter.InstantAverageVoltage, meter.InstantAverageCurrent, meter.InstantTotalCurrent,
+		})
+	}
+
+	rows["powerwalls"] = append(rows["powerwalls"], []interface{}{
+		metrics.SystemStatus.Timestamp, gatewayID, metrics.SystemStateOfEnergy.Percentage,
+		metrics.SystemStatus.BatteryTargetPower, metrics.SystemStatus.BatteryTargetReactivePower,
+		metrics.SystemStatus.NominalFullPackEnergyWattHours, metrics.SystemStatus.NominalEnergyRemainingWattHours,
+		metrics.SystemStatus.ExpectedEnergyRemaining, metrics.SystemStatus.MaxChargePowerWatts,
+		metrics.SystemStatus.MaxDischargePowerWatts, metrics.SystemStatus.InstantaneousMaxChargePower,
+		metrics.SystemStatus.InstantaneousMaxDischargePower, metrics.SystemStatus.GridServicesPower,
+		metrics.SystemStatus.SolarRealPowerLimit, metrics.SystemStatus.SystemIslandState,
+		metrics.SystemStatus.AvailableBlocks, metrics.SystemStatus.BlocksControlled,
+		metrics.Powerwalls.Updating, metrics.Powerwalls.Enumerating, metrics.Powerwalls.CheckingIfOffgrid,
+		metrics.Powerwalls.GridQualifying, metrics.Powerwalls.BubbleShedding,
+	})
+
+	for _, block := range metrics.SystemStatus.BatteryBlocks {
+		batteryID, err := s.deviceID(ctx, siteID, "battery", block.PackageSerialNumber, block.PackagePartNumber, block.Version, "")
+		if err != nil {
+			return err
+		}
+		disabledReasons := block.DisabledReasons
+		if disabledReasons == nil {
+			disabledReasons = []string{}
+		}
+		rows["battery_blocks"] = append(rows["battery_blocks"], []interface{}{
+			metrics.SystemStatus.Timestamp, gatewayID, batteryID, block.PinvState, block.PinvGridState,
+			block.OpSeqState, block.NominalEnergyRemainingWattHours, block.NominalFullPackEnergy,
+			block.POut, block.QOut, block.VOut, block.FOut, block.IOut,
+			int64(block.EnergyCharged), int64(block.EnergyDischarged), block.OffGrid, block.VfMode,
+			block.WobbleDetected, block.ChargePowerClamped, block.BackupReady, disabledReasons,
+		})
+	}
+
+	rows["configuration"] = append(rows["configuration"], []interface{}{
+		metrics.Operation.Timestamp, gatewayID, metrics.Operation.RealMode,
+		metrics.Operation.BackupReservePercent, metrics.Operation.FreqShiftLoadShedSoe,
+		metrics.Operation.FreqShiftLoadShedDeltaF, metrics.SiteInfo.NetMeterMode,
+		metrics.Sitemaster.Status, metrics.Sitemaster.Running, metrics.Sitemaster.ConnectedToTesla,
+		metrics.Sitemaster.PowerSupplyMode, metrics.SystemGridStatus.GridStatus,
+		metrics.SystemGridStatus.GridServicesActive,
+	})
+
+	for _, check := range metrics.NetworkConnectionTests.Checks {
+		rows["network_checks"] = append(rows["network_checks"], []interface{}{
+			metrics.NetworkConnectionTests.Timestamp, gatewayID, metrics.NetworkConnectionTests.Name,
+			check.Name, check.Status, nullTime(check.StartTime), nullTime(check.EndTime),
+		})
+	}
+
+	for _, fault := range metrics.SystemStatus.GridFaults {
+		rows["faults"] = append(rows["faults"], []interface{}{
+			metrics.SystemStatus.Timestamp, gatewayID, int64(fault.Timestamp), fault.AlertName,
+			fault.AlertIsFault, int64(fault.AlertRaw), fault.EcuType, fault.EcuPackagePartNumber,
+			fault.EcuPackageSerialNumber, fault.DecodedAlert,
+		})
+	}
+
+	for table, tableRows := range rows {
+		s.rows[table] = append(s.rows[table], tableRows...)
+	}
+	s.polls++
+	if s.polls < s.batchSize {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// Flush copies the buffered rows into PostgreSQL in a single transaction; the
+// buffer is emptied even if the copy fails so an unreachable database cannot
+// grow it without bound
+func (s *Sink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+func (s *Sink) flush() error {
+	if s.polls == 0 {
+		return nil
+	}
+	ctx := context.Background()
+
+	buffered := s.rows
+	s.rows = map[string][][]interface{}{}
+	s.polls = 0
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for table, rows := range buffered {
+		if len(rows) == 0 {
+			continue
+		}
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{table}, columns[table], pgx.CopyFromRows(rows))
+		if err != nil {
+			return fmt.Errorf("error when copying %d rows into %s, %s", len(rows), table, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
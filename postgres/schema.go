@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrations are applied in order and recorded in schema_migrations; existing
+// entries must never change, new schema changes are appended
+var migrations = []string{
+	`CREATE TABLE sites (
+		id        serial PRIMARY KEY,
+		site_name text NOT NULL,
+		grid_code text NOT NULL,
+		country   text NOT NULL,
+		state     text NOT NULL,
+		utility   text NOT NULL,
+		timezone  text NOT NULL,
+		UNIQUE (site_name, grid_code, country, state, utility)
+	);
+
+	CREATE TABLE devices (
+		id                serial PRIMARY KEY,
+		site_id           integer NOT NULL REFERENCES sites (id),
+		kind              text NOT NULL,
+		serial_number     text NOT NULL,
+		part_number       text NOT NULL,
+		firmware_version  text NOT NULL,
+		firmware_git_hash text NOT NULL,
+		first_seen        timestamptz NOT NULL DEFAULT now(),
+		last_seen         timestamptz NOT NULL DEFAULT now(),
+		UNIQUE (kind, serial_number)
+	);
+
+	CREATE TABLE meters (
+		time                    timestamptz NOT NULL,
+		gateway_id              integer NOT NULL REFERENCES devices (id),
+		meter                   text NOT NULL,
+		last_comm_time          timestamptz,
+		instant_power           double precision,
+		instant_reactive_power  double precision,
+		instant_apparent_power  double precision,
+		frequency               double precision,
+		energy_exported         double precision,
+		energy_imported         double precision,
+		instant_average_voltage double precision,
+		instant_average_current double precision,
+		instant_total_current   double precision
+	);
+	CREATE INDEX meters_gateway_time_idx ON meters (gateway_id, meter, time DESC);
+
+	CREATE TABLE powerwalls (
+		time                              timestamptz NOT NULL,
+		gateway_id                        integer NOT NULL REFERENCES devices (id),
+		charge_percent                    double precision,
+		battery_target_power              double precision,
+		battery_target_reactive_power     integer,
+		nominal_full_pack_energy          integer,
+		nominal_energy_remaining          integer,
+		expected_energy_remaining         integer,
+		max_charge_power                  integer,
+		max_discharge_power               double precision,
+		instantaneous_max_charge_power    integer,
+		instantaneous_max_discharge_power integer,
+		grid_services_power               double precision,
+		solar_real_power_limit            double precision,
+		system_island_state               text,
+		available_blocks                  integer,
+		blocks_controlled                 integer,
+		updating                          boolean,
+		enumerating                       boolean,
+		checking_if_offgrid               boolean,
+		grid_qualifying                   boolean,
+		bubble_shedding                   boolean
+	);
+	CREATE INDEX powerwalls_gateway_time_idx ON powerwalls (gateway_id, time DESC);
+
+	CREATE TABLE battery_blocks (
+		time                     timestamptz NOT NULL,
+		gateway_id               integer NOT NULL REFERENCES devices (id),
+		battery_id               integer NOT NULL REFERENCES devices (id),
+		pinv_state               text,
+		pinv_grid_state          text,
+		op_seq_state             text,
+		nominal_energy_remaining integer,
+		nominal_full_pack_energy integer,
+		p_out                    double precision,
+		q_out                    double precision,
+		v_out                    double precision,
+		f_out                    double precision,
+		i_out                    double precision,
+		energy_charged           bigint,
+		energy_discharged        bigint,
+		off_grid                 boolean,
+		vf_mode                  boolean,
+		wobble_detected          boolean,
+		charge_power_clamped     boolean,
+		backup_ready             boolean,
+		disabled_reasons         text[]
+	);
+	CREATE INDEX battery_blocks_battery_time_idx ON battery_blocks (battery_id, time DESC);
+
+	CREATE TABLE configuration (
+		time                          timestamptz NOT NULL,
+		gateway_id                    integer NOT NULL REFERENCES devices (id),
+		mode                          text,
+		backup_reserve_percent        double precision,
+		freq_shift_load_shed_soe      integer,
+		freq_shift_load_shed_delta_f  double precision,
+		net_meter_mode                text,
+		sitemaster_status             text,
+		sitemaster_running            boolean,
+		sitemaster_connected_to_tesla boolean,
+		sitemaster_power_supply_mode  boolean,
+		grid_status                   text,
+		grid_services_active          boolean
+	);
+	CREATE INDEX configuration_gateway_time_idx ON configuration (gateway_id, time DESC);
+
+	CREATE TABLE network_checks (
+		time       timestamptz NOT NULL,
+		gateway_id integer NOT NULL REFERENCES devices (id),
+		diagnostic text NOT NULL,
+		check_name text NOT NULL,
+		status     text,
+		start_time timestamptz,
+		end_time   timestamptz
+	);
+	CREATE INDEX network_checks_gateway_time_idx ON network_checks (gateway_id, time DESC);
+
+	CREATE TABLE faults (
+		time              timestamptz NOT NULL,
+		gateway_id        integer NOT NULL REFERENCES devices (id),
+		fault_timestamp   bigint,
+		alert_name        text NOT NULL,
+		is_fault          boolean,
+		alert_raw         bigint,
+		ecu_type          text,
+		ecu_part_number   text,
+		ecu_serial_number text,
+		decoded_alert     jsonb
+	);
+	CREATE INDEX faults_gateway_time_idx ON faults (gateway_id, time DESC);`,
+}
+
+// hypertables are converted to TimescaleDB hypertables when the extension is
+// available
+var hypertables = []string{"meters", "powerwalls", "battery_blocks", "configuration", "network_checks", "faults"}
+
+// Migrate applies any pending migrations and, when TimescaleDB is available,
+// converts the time series tables to hypertables
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("error when creating schema_migrations, %s", err)
+	}
+
+	var current int
+	err = pool.QueryRow(ctx, `SELECT coalesce(max(version), 0) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("error when reading schema version, %s", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, migrations[i])
+		if err == nil {
+			_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, i+1)
+		}
+		if err == nil {
+			err = tx.Commit(ctx)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("error when applying migration %d, %s", i+1, err)
+		}
+	}
+
+	var timescale bool
+	err = pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'timescaledb')`).Scan(&timescale)
+	if err != nil {
+		return fmt.Errorf("error when checking for TimescaleDB, %s", err)
+	}
+	if !timescale {
+		return nil
+	}
+
+	_, err = pool.Exec(ctx, `CREATE EXTENSION IF NOT EXISTS timescaledb`)
+	if err != nil {
+		return fmt.Errorf("error when creating the TimescaleDB extension, %s", err)
+	}
+	for _, table := range hypertables {
+		_, err = pool.Exec(ctx, `SELECT create_hypertable($1::regclass, 'time', if_not_exists => TRUE, migrate_data => TRUE)`, table)
+		if err != nil {
+			return fmt.Errorf("error when creating hypertable %s, %s", table, err)
+		}
+	}
+
+	return nil
+}
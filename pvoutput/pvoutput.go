@@ -0,0 +1,324 @@
+// Package pvoutput uploads generation, consumption and voltage status to
+// PVOutput.org.
+package pvoutput
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxAge is how old a status PVOutput accepts
+const maxAge = 14 * 24 * time.Hour
+
+// uploadInterval is how often pending statuses are uploaded
+const uploadInterval = time.Minute
+
+// status is one PVOutput status; energies are lifetime counters in Wh, sent
+// with the cumulative flag so PVOutput derives the daily totals
+type status struct {
+	time              time.Time
+	energyGeneration  float64
+	powerGeneration   float64
+	energyConsumption float64
+	powerConsumption  float64
+	voltage           float64
+}
+
+// slot accumulates the samples within one status interval
+type slot struct {
+	start             time.Time
+	location          *time.Location
+	samples           float64
+	powerGeneration   float64
+	powerConsumption  float64
+	voltage           float64
+	energyGeneration  float64
+	energyConsumption float64
+}
+
+// Uploader averages samples into statuses at the configured interval and
+// uploads them, in batches when catching up
+type Uploader struct {
+	conf     config.PVOutput
+	timezone string
+	client   *http.Client
+
+	mu      sync.Mutex
+	current *slot
+	pending []status
+
+	// resumeAt holds off uploads until the rate limit resets
+	resumeAt time.Time
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	errCh   chan error
+	errRead int32
+}
+
+// New validates the configuration and starts uploading in the background
+func New(conf *config.Configuration) (*Uploader, error) {
+	if conf.PVOutput.APIKey == "" || conf.PVOutput.SystemID == "" {
+		return nil, fmt.Errorf("must configure both apiKey and systemId")
+	}
+	if conf.PVOutput.URL == "" {
+		conf.PVOutput.URL = "https://pvoutput.org"
+	}
+	if conf.PVOutput.Interval == 0 {
+		conf.PVOutput.Interval = 5 * time.Minute
+	}
+	if conf.PVOutput.Interval%(5*time.Minute) != 0 || conf.PVOutput.Interval > 15*time.Minute {
+		return nil, fmt.Errorf("interval must be one of 5m, 10m or 15m to match the system's status interval")
+	}
+	if conf.PVOutput.BatchSize == 0 {
+		conf.PVOutput.BatchSize = 30
+	}
+	if conf.PVOutput.Timeout == 0 {
+		conf.PVOutput.Timeout = 30 * time.Second
+	}
+
+	u := &Uploader{
+		conf:     conf.PVOutput,
+		timezone: conf.Energy.Timezone,
+		client:   &http.Client{Timeout: conf.PVOutput.Timeout},
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+		errCh:    make(chan error),
+	}
+	go u.run()
+
+	return u, nil
+}
+
+// Record adds a sample to the current interval, queueing the previous
+// interval's status once a sample from a later interval arrives
+func (u *Uploader) Record(metrics model.Teg) {
+	ts := metrics.Meters.Timestamp
+	start := ts.Truncate(u.conf.Interval)
+
+	// PVOutput takes statuses in the system's local time, which is the
+	// site's rather than the host's
+	loc, err := energy.Location(u.timezone, metrics)
+	if err != nil {
+		u.reportError(err)
+		loc = time.Local
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.current != nil && !start.Equal(u.current.start) {
+		if start.After(u.current.start) {
+			u.pending = append(u.pending, u.current.status(u.conf.Interval))
+		}
+		u.current = nil
+	}
+	if u.current == nil {
+		u.current = &slot{start: start, location: loc}
+	}
+
+	solar := metrics.Meters.Solar.InstantPowerWatts
+	if solar < 0 {
+		solar = 0
+	}
+	load := metrics.Meters.Load.InstantPowerWatts
+	if load < 0 {
+		load = 0
+	}
+
+	s := u.current
+	s.samples++
+	s.powerGeneration += solar
+	s.powerConsumption += load
+	s.voltage += metrics.Meters.Site.InstantAverageVoltage
+	s.energyGeneration = metrics.Meters.Solar.EnergyExportedWatts
+	s.energyConsumption = metrics.Meters.Load.EnergyImportedWatts
+}
+
+// status averages the slot into the status reported at the end of it
+func (s *slot) status(interval time.Duration) status {
+	return status{
+		time:              s.start.Add(interval).In(s.location),
+		energyGeneration:  s.energyGeneration,
+		powerGeneration:   s.powerGeneration / s.samples,
+		energyConsumption: s.energyConsumption,
+		powerConsumption:  s.powerConsumption / s.samples,
+		voltage:           s.voltage / s.samples,
+	}
+}
+
+// Close stops uploading after a last attempt at the pending statuses; the
+// interval still in progress is discarded
+func (u *Uploader) Close() {
+	close(u.stopCh)
+	<-u.doneCh
+	u.upload()
+}
+
+// Errors returns the channel of upload errors
+func (u *Uploader) Errors() <-chan error {
+	atomic.StoreInt32(&u.errRead, 1)
+	return u.errCh
+}
+
+func (u *Uploader) reportError(err error) {
+	if atomic.LoadInt32(&u.errRead) == 1 {
+		u.errCh <- err
+	}
+}
+
+func (u *Uploader) run() {
+	defer close(u.doneCh)
+	ticker := time.NewTicker(uploadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			u.upload()
+		case <-u.stopCh:
+			return
+		}
+	}
+}
+
+// upload sends the pending statuses, oldest first, until they are all
+// uploaded, an upload fails or the rate limit is reached
+func (u *Uploader) upload() {
+	for time.Now().After(u.resumeAt) {
+		u.mu.Lock()
+		// PVOutput rejects statuses older than it accepts, so drop them
+		cutoff := time.Now().Add(-maxAge)
+		for len(u.pending) > 0 && u.pending[0].time.Before(cutoff) {
+			u.pending = u.pending[1:]
+		}
+		n := len(u.pending)
+		if uint(n) > u.conf.BatchSize {
+			n = int(u.conf.BatchSize)
+		}
+		batch := append([]status(nil), u.pending[:n]...)
+		u.mu.Unlock()
+
+		if len(batch) == 0 {
+			return
+		}
+
+		rejected, err := u.send(batch)
+		if err != nil {
+			u.reportError(fmt.Errorf("error when uploading %d statuses, %s", len(batch), err))
+			return
+		}
+		if rejected > 0 {
+			u.reportError(fmt.Errorf("PVOutput did not add %d of %d statuses", rejected, len(batch)))
+		}
+
+		u.mu.Lock()
+		u.pending = u.pending[len(batch):]
+		u.mu.Unlock()
+	}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', 0, 64)
+}
+
+// send uploads a single status with the add status service, or several with
+// the add batch status service, returning how many statuses were not added
+func (u *Uploader) send(batch []status) (int, error) {
+	form := url.Values{}
+	form.Set("c1", "1")
+
+	var service string
+	if len(batch) == 1 {
+		service = "/service/r2/addstatus.jsp"
+		s := batch[0]
+		form.Set("d", s.time.Format("20060102"))
+		form.Set("t", s.time.Format("15:04"))
+		form.Set("v1", formatValue(s.energyGeneration))
+		form.Set("v2", formatValue(s.powerGeneration))
+		form.Set("v3", formatValue(s.energyConsumption))
+		form.Set("v4", formatValue(s.powerConsumption))
+		form.Set("v6", strconv.FormatFloat(s.voltage, 'f', 1, 64))
+	} else {
+		service = "/service/r2/addbatchstatus.jsp"
+		records := make([]string, len(batch))
+		for i, s := range batch {
+			records[i] = strings.Join([]string{
+				s.time.Format("20060102"),
+				s.time.Format("15:04"),
+				formatValue(s.energyGeneration),
+				formatValue(s.powerGeneration),
+				formatValue(s.energyConsumption),
+				formatValue(s.powerConsumption),
+				"",
+				strconv.FormatFloat(s.voltage, 'f', 1, 64),
+			}, ",")
+		}
+		form.Set("data", strings.Join(records, ";"))
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimSuffix(u.conf.URL, "/")+service, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Pvoutput-Apikey", u.conf.APIKey)
+	req.Header.Set("X-Pvoutput-SystemId", u.conf.SystemID)
+	req.Header.Set("X-Rate-Limit", "1")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return 0, err
+	}
+
+	reset := rateLimitReset(resp.Header)
+	if resp.Header.Get("X-Rate-Limit-Remaining") == "0" {
+		u.resumeAt = reset
+	}
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(string(body), "Exceeded") {
+		u.resumeAt = reset
+		return 0, fmt.Errorf("rate limit exceeded, resuming at %s", reset.Format(time.RFC3339))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("expected HTTP status code 200 but got %d; raw body %s", resp.StatusCode, body)
+	}
+
+	// The batch service answers date,time,added for each status
+	rejected := 0
+	if len(batch) > 1 {
+		for _, result := range strings.Split(strings.TrimSpace(string(body)), ";") {
+			if strings.HasSuffix(result, ",0") {
+				rejected++
+			}
+		}
+	}
+
+	return rejected, nil
+}
+
+// rateLimitReset reads when the hourly rate limit resets, assuming an hour
+// from now if PVOutput does not say
+func rateLimitReset(header http.Header) time.Time {
+	reset, err := strconv.ParseInt(header.Get("X-Rate-Limit-Reset"), 10, 64)
+	if err != nil {
+		return time.Now().Add(time.Hour)
+	}
+	return time.Unix(reset, 0)
+}
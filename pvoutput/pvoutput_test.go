@@ -0,0 +1,152 @@
+package pvoutput
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+)
+
+// pvoutputServer is a stand-in for the PVOutput status services
+type pvoutputServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	paths    []string
+	forms    []url.Values
+	headers  []http.Header
+	limitHit bool
+}
+
+func newPVOutputServer(t *testing.T) *pvoutputServer {
+	s := &pvoutputServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("error when parsing form, %s", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.limitHit {
+			w.Header().Set("X-Rate-Limit-Reset", "4102444800")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Forbidden 403: Exceeded 60 requests per hour"))
+			return
+		}
+		s.paths = append(s.paths, r.URL.Path)
+		s.forms = append(s.forms, r.PostForm)
+		s.headers = append(s.headers, r.Header)
+		if r.URL.Path == "/service/r2/addbatchstatus.jsp" {
+			var results []string
+			for _, record := range strings.Split(r.PostForm.Get("data"), ";") {
+				fields := strings.Split(record, ",")
+				results = append(results, fields[0]+","+fields[1]+",1")
+			}
+			w.Write([]byte(strings.Join(results, ";")))
+			return
+		}
+		w.Write([]byte("OK 200: Added Status"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestUploader(t *testing.T, s *pvoutputServer) *Uploader {
+	conf := &config.Configuration{}
+	conf.Energy.Timezone = "Asia/Kathmandu"
+	conf.PVOutput.APIKey = "key"
+	conf.PVOutput.SystemID = "42"
+	conf.PVOutput.URL = s.URL
+	u, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func sample(ts time.Time, solar, load float64) model.Teg {
+	var metrics model.Teg
+	metrics.Meters.Timestamp = ts
+	metrics.Meters.Solar.InstantPowerWatts = solar
+	metrics.Meters.Solar.EnergyExportedWatts = 1000000
+	metrics.Meters.Load.InstantPowerWatts = load
+	metrics.Meters.Load.EnergyImportedWatts = 2000000
+	metrics.Meters.Site.InstantAverageVoltage = 240
+	return metrics
+}
+
+func TestUploadsInSiteTimeOnClose(t *testing.T) {
+	s := newPVOutputServer(t)
+	u := newTestUploader(t, s)
+
+	start := time.Now().Truncate(5 * time.Minute).Add(-time.Hour)
+	u.Record(sample(start.Add(time.Minute), 3000, 1000))
+	u.Record(sample(start.Add(3*time.Minute), 5000, 2000))
+	u.Record(sample(start.Add(6*time.Minute), 0, 0))
+	u.Close()
+
+	if len(s.paths) != 1 || s.paths[0] != "/service/r2/addstatus.jsp" {
+		t.Fatalf("expected one status upload on close but got %v", s.paths)
+	}
+	form := s.forms[0]
+	end := start.Add(5 * time.Minute).In(time.FixedZone("NPT", 5*3600+45*60))
+	if form.Get("d") != end.Format("20060102") || form.Get("t") != end.Format("15:04") {
+		t.Errorf("expected status at %s in the site's time zone but got %s %s", end, form.Get("d"), form.Get("t"))
+	}
+	if form.Get("v2") != "4000" || form.Get("v4") != "1500" || form.Get("v1") != "1000000" || form.Get("c1") != "1" {
+		t.Errorf("unexpected status %v", form)
+	}
+	if s.headers[0].Get("X-Pvoutput-Apikey") != "key" || s.headers[0].Get("X-Pvoutput-SystemId") != "42" {
+		t.Errorf("unexpected headers %v", s.headers[0])
+	}
+}
+
+func TestUploadsBacklogInBatches(t *testing.T) {
+	s := newPVOutputServer(t)
+	u := newTestUploader(t, s)
+
+	start := time.Now().Truncate(5 * time.Minute).Add(-2 * time.Hour)
+	for i := 0; i <= 3; i++ {
+		u.Record(sample(start.Add(time.Duration(i)*5*time.Minute), 1000, 500))
+	}
+	u.Close()
+
+	if len(s.paths) != 1 || s.paths[0] != "/service/r2/addbatchstatus.jsp" {
+		t.Fatalf("expected one batch upload but got %v", s.paths)
+	}
+	if records := strings.Split(s.forms[0].Get("data"), ";"); len(records) != 3 {
+		t.Errorf("expected 3 statuses but got %v", records)
+	}
+}
+
+func TestStopsAtRateLimit(t *testing.T) {
+	s := newPVOutputServer(t)
+	s.limitHit = true
+	u := newTestUploader(t, s)
+	errs := u.Errors()
+	done := make(chan string)
+	go func() {
+		err := <-errs
+		done <- err.Error()
+	}()
+
+	start := time.Now().Truncate(5 * time.Minute).Add(-time.Hour)
+	u.Record(sample(start, 1000, 500))
+	u.Record(sample(start.Add(5*time.Minute), 1000, 500))
+	u.Close()
+
+	if err := <-done; !strings.Contains(err, "rate limit exceeded") {
+		t.Errorf("expected a rate limit error but got %s", err)
+	}
+	if !u.resumeAt.After(time.Now()) {
+		t.Errorf("expected uploads to be held off but resume at %s", u.resumeAt)
+	}
+	if len(u.pending) != 1 {
+		t.Errorf("expected the status to stay pending but got %d", len(u.pending))
+	}
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// QueryOptions selects the fields and time range returned by Query
+type QueryOptions struct {
+	// Fields are field names, optionally qualified as measurement.field
+	Fields []string
+	Start  time.Time
+	End    time.Time
+	// Every selects a rollup resolution; zero returns the raw samples
+	Every time.Duration
+	// Stat is the rollup statistic, one of mean, min, max or count
+	Stat string
+}
+
+// Result holds the queried series as columns over their combined times
+type Result struct {
+	Columns []string
+	Times   []time.Time
+	// Rows holds a value per column for each time, nil where a series has
+	// no value at that time
+	Rows [][]interface{}
+}
+
+type querySeries struct {
+	id    int64
+	label string
+	tags  string
+}
+
+// Query reads the selected fields from the SQLite store
+func Query(conf *config.Configuration, opts QueryOptions) (*Result, error) {
+	if len(opts.Fields) == 0 {
+		return nil, fmt.Errorf("no fields selected")
+	}
+	if opts.Stat == "" {
+		opts.Stat = "mean"
+	}
+	switch opts.Stat {
+	case "mean", "min", "max", "count":
+	default:
+		return nil, fmt.Errorf("unsupported statistic %s, must be one of mean, min, max or count", opts.Stat)
+	}
+
+	db, err := open(conf.SQLite.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var series []querySeries
+	for _, selector := range opts.Fields {
+		query := `SELECT id, measurement, tags, field FROM series WHERE field = ? ORDER BY measurement, tags`
+		args := []interface{}{selector}
+		if i := strings.LastIndex(selector, "."); i >= 0 {
+			query = `SELECT id, measurement, tags, field FROM series WHERE measurement = ? AND field = ? ORDER BY tags`
+			args = []interface{}{conf.InfluxDB.MeasurementPrefix + selector[:i], selector[i+1:]}
+		}
+
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		var matched []querySeries
+		for rows.Next() {
+			var s querySeries
+			var measurement, field string
+			err = rows.Scan(&s.id, &measurement, &s.tags, &field)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			s.label = measurement + "." + field
+			matched = append(matched, s)
+		}
+		rows.Close()
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no series found for %s", selector)
+		}
+
+		// Tell apart series of the same field by the tags whose values differ
+		if len(matched) > 1 {
+			for i := range matched {
+				matched[i].label += "{" + distinctTags(matched, i) + "}"
+			}
+		}
+		series = append(series, matched...)
+	}
+
+	byTime := map[int64][]interface{}{}
+	for col, s := range series {
+		var query string
+		args := []interface{}{s.id, opts.Start.UnixMilli(), opts.End.UnixMilli()}
+		if opts.Every == 0 {
+			query = `SELECT time, value FROM samples WHERE series_id = ? AND time >= ? AND time < ? ORDER BY time`
+		} else {
+			query = `SELECT time, ` + opts.Stat + ` FROM rollups WHERE series_id = ? AND time >= ? AND time < ? AND resolution = ? ORDER BY time`
+			args = append(args, int64(opts.Every/time.Second))
+		}
+
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var ts int64
+			var value interface{}
+			err = rows.Scan(&ts, &value)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if _, ok := byTime[ts]; !ok {
+				byTime[ts] = make([]interface{}, len(series))
+			}
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			byTime[ts][col] = value
+		}
+		rows.Close()
+	}
+
+	result := &Result{}
+	for _, s := range series {
+		result.Columns = append(result.Columns, s.label)
+	}
+	times := make([]int64, 0, len(byTime))
+	for ts := range byTime {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, ts := range times {
+		result.Times = append(result.Times, time.UnixMilli(ts))
+		result.Rows = append(result.Rows, byTime[ts])
+	}
+
+	return result, nil
+}
+
+// distinctTags returns the tags of series i whose values are not shared by
+// every matched series
+func distinctTags(matched []querySeries, i int) string {
+	common := map[string]int{}
+	for _, s := range matched {
+		for _, pair := range strings.Split(s.tags, ",") {
+			common[pair]++
+		}
+	}
+	var distinct []string
+	for _, pair := range strings.Split(matched[i].tags, ",") {
+		if common[pair] < len(matched) {
+			distinct = append(distinct, pair)
+		}
+	}
+	return strings.Join(distinct, ",")
+}
+
+// Print writes the result as a table, CSV or JSON
+func (r *Result) Print(w io.Writer, format string) error {
+	switch format {
+	case "", "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "time\t"+strings.Join(r.Columns, "\t"))
+		for i, ts := range r.Times {
+			fmt.Fprintln(tw, ts.Format(time.RFC3339)+"\t"+strings.Join(formatRow(r.Rows[i]), "\t"))
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(append([]string{"time"}, r.Columns...))
+		for i, ts := range r.Times {
+			cw.Write(append([]string{ts.Format(time.RFC3339Nano)}, formatRow(r.Rows[i])...))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		records := make([]map[string]interface{}, 0, len(r.Times))
+		for i, ts := range r.Times {
+			record := map[string]interface{}{"time": ts.Format(time.RFC3339Nano)}
+			for j, column := range r.Columns {
+				if r.Rows[i][j] != nil {
+					record[column] = r.Rows[i][j]
+				}
+			}
+			records = append(records, record)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+	return fmt.Errorf("unsupported format %s, must be one of table, csv or json", format)
+}
+
+func formatRow(row []interface{}) []string {
+	values := make([]string, len(row))
+	for i, v := range row {
+		if v != nil {
+			values[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	return values
+}
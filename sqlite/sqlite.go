@@ -0,0 +1,377 @@
+// Package sqlite stores polled points in an embedded SQLite database with
+// retention pruning and rollups, for installs without external services.
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	influxAPI "github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	lp "github.com/influxdata/line-protocol"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	_ "modernc.org/sqlite" // registers the sqlite driver
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// schema keeps one row per series (measurement, tag set and field) so that the
+// samples and rollups only store integer ids, millisecond times and values
+const schema = `
+CREATE TABLE IF NOT EXISTS series (
+	id          INTEGER PRIMARY KEY,
+	measurement TEXT NOT NULL,
+	tags        TEXT NOT NULL,
+	field       TEXT NOT NULL,
+	UNIQUE (measurement, tags, field)
+);
+
+CREATE TABLE IF NOT EXISTS samples (
+	series_id INTEGER NOT NULL,
+	time      INTEGER NOT NULL,
+	value,
+	PRIMARY KEY (series_id, time)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS rollups (
+	series_id  INTEGER NOT NULL,
+	resolution INTEGER NOT NULL,
+	time       INTEGER NOT NULL,
+	mean       REAL NOT NULL,
+	min        REAL NOT NULL,
+	max        REAL NOT NULL,
+	count      INTEGER NOT NULL,
+	PRIMARY KEY (series_id, resolution, time)
+) WITHOUT ROWID;
+`
+
+// pruneInterval is how often expired samples and rollups are deleted
+const pruneInterval = time.Hour
+
+// defaultRollups are kept when none are configured
+var defaultRollups = []config.SQLiteRollup{
+	{Every: time.Minute, Retention: 90 * 24 * time.Hour},
+	{Every: time.Hour},
+}
+
+// Store is a WriteAPI which buffers points and periodically commits them to
+// SQLite, updating the rollups as it goes
+type Store struct {
+	db           *sql.DB
+	measurements map[string]bool
+	retention    time.Duration
+	rollups      []config.SQLiteRollup
+	series       map[string]int64
+	lastPrune    time.Time
+
+	mu     sync.Mutex
+	points []*write.Point
+
+	flushCh chan chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	errCh   chan error
+	errRead int32
+}
+
+// open opens the SQLite database with settings suited to SD cards and a
+// single writer
+func open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// Open opens or creates the SQLite database and starts committing points
+func Open(conf *config.Configuration) (*Store, error) {
+	db, err := open(conf.SQLite.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(schema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error when creating schema, %s", err)
+	}
+
+	if conf.SQLite.FlushInterval == 0 {
+		conf.SQLite.FlushInterval = 10
+	}
+
+	if conf.SQLite.Rollups == nil {
+		conf.SQLite.Rollups = defaultRollups
+	}
+	for _, rollup := range conf.SQLite.Rollups {
+		// Rollups are keyed by their interval in seconds
+		if rollup.Every < time.Second || rollup.Every%time.Second != 0 {
+			db.Close()
+			return nil, fmt.Errorf("rollup interval %s must be a whole number of seconds", rollup.Every)
+		}
+	}
+
+	var measurements map[string]bool
+	if len(conf.SQLite.Measurements) > 0 {
+		measurements = map[string]bool{}
+		for _, m := range conf.SQLite.Measurements {
+			measurements[conf.InfluxDB.MeasurementPrefix+m] = true
+		}
+	}
+
+	s := &Store{
+		db:           db,
+		measurements: measurements,
+		retention:    conf.SQLite.Retention,
+		rollups:      conf.SQLite.Rollups,
+		series:       map[string]int64{},
+		flushCh:      make(chan chan struct{}),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+		errCh:        make(chan error),
+	}
+	go s.run(time.Duration(conf.SQLite.FlushInterval) * time.Second)
+
+	return s, nil
+}
+
+func (s *Store) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.commit()
+		case done := <-s.flushCh:
+			s.commit()
+			close(done)
+		case <-s.stopCh:
+			s.commit()
+			close(s.doneCh)
+			return
+		}
+	}
+}
+
+// Close commits the buffered points and closes the database
+func (s *Store) Close() {
+	close(s.stopCh)
+	<-s.doneCh
+	s.db.Close()
+}
+
+// WritePoint buffers a point for the next commit
+func (s *Store) WritePoint(point *write.Point) {
+	if s.measurements != nil && !s.measurements[point.Name()] {
+		return
+	}
+	s.mu.Lock()
+	s.points = append(s.points, point)
+	s.mu.Unlock()
+}
+
+// WriteRecord parses a line protocol record and buffers its points
+func (s *Store) WriteRecord(line string) {
+	metrics, err := lp.NewParser(lp.NewMetricHandler()).Parse([]byte(line))
+	if err != nil {
+		s.reportError(fmt.Errorf("error when parsing line protocol, %s", err))
+		return
+	}
+	for _, m := range metrics {
+		p := write.NewPointWithMeasurement(m.Name()).SetTime(m.Time())
+		for _, tag := range m.TagList() {
+			p.AddTag(tag.Key, tag.Value)
+		}
+		for _, field := range m.FieldList() {
+			p.AddField(field.Key, field.Value)
+		}
+		s.WritePoint(p)
+	}
+}
+
+// Flush commits the buffered points and waits for the commit to finish
+func (s *Store) Flush() {
+	done := make(chan struct{})
+	s.flushCh <- done
+	<-done
+}
+
+// Errors returns the channel of commit errors
+func (s *Store) Errors() <-chan error {
+	atomic.StoreInt32(&s.errRead, 1)
+	return s.errCh
+}
+
+// SetWriteFailedCallback is a no-op, failed commits are reported on Errors
+func (s *Store) SetWriteFailedCallback(cb influxAPI.WriteFailedCallback) {
+}
+
+func (s *Store) reportError(err error) {
+	if atomic.LoadInt32(&s.errRead) == 1 {
+		s.errCh <- err
+	}
+}
+
+// seriesKey renders a measurement, its tags sorted by key and a field as the
+// unique key of a series
+func seriesKey(measurement, tags, field string) string {
+	return measurement + "\x00" + tags + "\x00" + field
+}
+
+// formatTags renders tags as a sorted k=v list
+func formatTags(tags []*lp.Tag) string {
+	pairs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// commit writes the buffered points in a single transaction
+func (s *Store) commit() {
+	s.mu.Lock()
+	points := s.points
+	s.points = nil
+	s.mu.Unlock()
+
+	if len(points) > 0 {
+		err := s.insert(points)
+		if err != nil {
+			s.reportError(fmt.Errorf("error when committing %d points to SQLite, %s", len(points), err))
+		}
+	}
+
+	if time.Since(s.lastPrune) >= pruneInterval {
+		err := s.prune()
+		if err != nil {
+			s.reportError(fmt.Errorf("error when pruning SQLite, %s", err))
+		}
+		s.lastPrune = time.Now()
+	}
+}
+
+func (s *Store) insert(points []*write.Point) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	seriesStmt, err := tx.Prepare(`INSERT INTO series (measurement, tags, field) VALUES (?, ?, ?)
+		ON CONFLICT (measurement, tags, field) DO UPDATE SET field = excluded.field
+		RETURNING id`)
+	if err != nil {
+		return err
+	}
+	sampleStmt, err := tx.Prepare(`INSERT OR REPLACE INTO samples (series_id, time, value) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	rollupStmt, err := tx.Prepare(`INSERT INTO rollups (series_id, resolution, time, mean, min, max, count) VALUES (?, ?, ?, ?, ?, ?, 1)
+		ON CONFLICT (series_id, resolution, time) DO UPDATE SET
+			mean = (mean * count + excluded.mean) / (count + 1),
+			min = min(min, excluded.min),
+			max = max(max, excluded.max),
+			count = count + 1`)
+	if err != nil {
+		return err
+	}
+
+	created := map[string]int64{}
+	for _, point := range points {
+		tags := formatTags(point.TagList())
+		ts := point.Time().UnixMilli()
+		for _, field := range point.FieldList() {
+			key := seriesKey(point.Name(), tags, field.Key)
+			id, ok := s.series[key]
+			if !ok {
+				id, ok = created[key]
+			}
+			if !ok {
+				err = seriesStmt.QueryRow(point.Name(), tags, field.Key).Scan(&id)
+				if err != nil {
+					return err
+				}
+				created[key] = id
+			}
+
+			var value interface{}
+			var numeric bool
+			var number float64
+			switch v := field.Value.(type) {
+			case bool:
+				value = 0
+				if v {
+					value = 1
+				}
+			case float64:
+				value, number, numeric = v, v, true
+			case int64:
+				value, number, numeric = v, float64(v), true
+			case uint64:
+				value, number, numeric = int64(v), float64(v), true
+			default:
+				value = v
+			}
+
+			_, err = sampleStmt.Exec(id, ts, value)
+			if err != nil {
+				return err
+			}
+
+			if !numeric {
+				continue
+			}
+			for _, rollup := range s.rollups {
+				resolution := rollup.Every.Milliseconds()
+				_, err = rollupStmt.Exec(id, resolution/1000, ts-ts%resolution, number, number, number)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	// Only cache series ids once they are committed
+	for key, id := range created {
+		s.series[key] = id
+	}
+
+	return nil
+}
+
+// prune deletes samples and rollups older than their retention; a zero
+// retention keeps them forever
+func (s *Store) prune() error {
+	now := time.Now()
+	if s.retention > 0 {
+		_, err := s.db.Exec(`DELETE FROM samples WHERE time < ?`, now.Add(-s.retention).UnixMilli())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rollup := range s.rollups {
+		if rollup.Retention <= 0 {
+			continue
+		}
+		_, err := s.db.Exec(`DELETE FROM rollups WHERE resolution = ? AND time < ?`,
+			int64(rollup.Every/time.Second), now.Add(-rollup.Retention).UnixMilli())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
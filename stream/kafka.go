@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"strconv"
+	"time"
+)
+
+// kafkaPublisher writes messages keyed by gateway ID so that each gateway's
+// snapshots stay ordered within a partition
+type kafkaPublisher struct {
+	writer  *kafka.Writer
+	headers []kafka.Header
+}
+
+func newKafka(conf config.Stream, contentType string) (*kafkaPublisher, error) {
+	transport := &kafka.Transport{}
+	if conf.Kafka.TLS {
+		transport.TLS = &tls.Config{InsecureSkipVerify: conf.Kafka.SkipVerifySsl}
+	}
+	if conf.Kafka.Username != "" {
+		transport.SASL = plain.Mechanism{
+			Username: conf.Kafka.Username,
+			Password: conf.Kafka.Password,
+		}
+	}
+
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(conf.Kafka.Brokers...),
+			Topic:        conf.Kafka.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// Batches are collected by the Stream, so write them immediately
+			BatchSize:    int(conf.BatchSize),
+			BatchTimeout: time.Millisecond,
+			Transport:    transport,
+		},
+		headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(contentType)},
+			{Key: "schema-version", Value: []byte(strconv.Itoa(SchemaVersion))},
+		},
+	}, nil
+}
+
+func (k *kafkaPublisher) publish(batch []message) error {
+	msgs := make([]kafka.Message, len(batch))
+	for i, m := range batch {
+		msgs[i] = kafka.Message{
+			Key:     []byte(m.key),
+			Value:   m.value,
+			Headers: k.headers,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return k.writer.WriteMessages(ctx, msgs...)
+}
+
+func (k *kafkaPublisher) close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"strconv"
+)
+
+// natsPublisher publishes to a subject bound to a JetStream stream so that
+// every message is acknowledged once stored; the message ID lets JetStream
+// drop duplicates from retried batches
+type natsPublisher struct {
+	conn        *nats.Conn
+	js          jetstream.JetStream
+	subject     string
+	contentType string
+}
+
+func newNATS(conf config.Stream, contentType string) (*natsPublisher, error) {
+	opts := []nats.Option{
+		nats.Name("tesla-energy-stats-collector"),
+		nats.MaxReconnects(-1),
+	}
+	if conf.NATS.Token != "" {
+		opts = append(opts, nats.Token(conf.NATS.Token))
+	}
+	if conf.NATS.Credentials != "" {
+		opts = append(opts, nats.UserCredentials(conf.NATS.Credentials))
+	}
+
+	conn, err := nats.Connect(conf.NATS.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error when connecting to NATS, %s", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error when creating JetStream context, %s", err)
+	}
+
+	return &natsPublisher{
+		conn:        conn,
+		js:          js,
+		subject:     conf.NATS.Subject,
+		contentType: contentType,
+	}, nil
+}
+
+func (n *natsPublisher) publish(batch []message) error {
+	futures := make([]jetstream.PubAckFuture, 0, len(batch))
+	for _, m := range batch {
+		msg := nats.NewMsg(n.subject)
+		msg.Data = m.value
+		msg.Header.Set("Content-Type", n.contentType)
+		msg.Header.Set("Schema-Version", strconv.Itoa(SchemaVersion))
+		msg.Header.Set("Gateway-Id", m.key)
+
+		future, err := n.js.PublishMsgAsync(msg, jetstream.WithMsgID(m.id))
+		if err != nil {
+			return err
+		}
+		futures = append(futures, future)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for acknowledgement")
+		}
+	}
+
+	return nil
+}
+
+func (n *natsPublisher) close() error {
+	return n.conn.Drain()
+}
@@ -0,0 +1,362 @@
+package stream
+
+import (
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/stream/snapshotpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// snapshot converts a poll to the typed snapshot message; the fields the
+// gateway reports without a fixed shape become google.protobuf.Value
+func snapshot(metrics model.Teg) (*snapshotpb.Snapshot, error) {
+	c := &converter{}
+	teg := &snapshotpb.Teg{
+		Meters: &snapshotpb.Meters{
+			Timestamp: timestamp(metrics.Meters.Timestamp),
+			Site:      meter(metrics.Meters.Site),
+			Battery:   meter(metrics.Meters.Battery),
+			Load:      meter(metrics.Meters.Load),
+			Solar:     meter(metrics.Meters.Solar),
+		},
+		MetersStatus: &snapshotpb.MetersStatus{
+			Timestamp: timestamp(metrics.MetersStatus.Timestamp),
+			Status:    metrics.MetersStatus.Status,
+			Errors:    c.value(metrics.MetersStatus.Errors),
+			Serial:    metrics.MetersStatus.Serial,
+		},
+		Operation: &snapshotpb.Operation{
+			Timestamp:               timestamp(metrics.Operation.Timestamp),
+			RealMode:                metrics.Operation.RealMode,
+			BackupReservePercent:    metrics.Operation.BackupReservePercent,
+			FreqShiftLoadShedSoe:    int64(metrics.Operation.FreqShiftLoadShedSoe),
+			FreqShiftLoadShedDeltaF: metrics.Operation.FreqShiftLoadShedDeltaF,
+		},
+		Powerwalls: c.powerwalls(metrics.Powerwalls),
+		SiteInfo: &snapshotpb.SiteInfo{
+			Timestamp:              timestamp(metrics.SiteInfo.Timestamp),
+			MeasuredFrequency:      metrics.SiteInfo.MeasuredFrequency,
+			MaxSystemEnergyKwh:     metrics.SiteInfo.MaxSystemEnergyKwh,
+			MaxSystemPowerKw:       metrics.SiteInfo.MaxSystemPowerKw,
+			SiteName:               metrics.SiteInfo.SiteName,
+			Timezone:               metrics.SiteInfo.Timezone,
+			NetMeterMode:           metrics.SiteInfo.NetMeterMode,
+			MaxSiteMeterPowerKw:    int64(metrics.SiteInfo.MaxSiteMeterPowerKw),
+			MinSiteMeterPowerKw:    int64(metrics.SiteInfo.MinSiteMeterPowerKw),
+			NominalSystemEnergyKwh: metrics.SiteInfo.NominalSystemEnergyKwh,
+			NominalSystemPowerKw:   metrics.SiteInfo.NominalSystemPowerKw,
+			PanelMaxCurrent:        int64(metrics.SiteInfo.PanelMaxCurrent),
+			GridCode: &snapshotpb.GridCode{
+				GridCode:           metrics.SiteInfo.GridCode.GridCode,
+				GridVoltageSetting: int64(metrics.SiteInfo.GridCode.GridVoltageSetting),
+				GridFreqSetting:    int64(metrics.SiteInfo.GridCode.GridFreqSetting),
+				GridPhaseSetting:   metrics.SiteInfo.GridCode.GridPhaseSetting,
+				Country:            metrics.SiteInfo.GridCode.Country,
+				State:              metrics.SiteInfo.GridCode.State,
+				Utility:            metrics.SiteInfo.GridCode.Utility,
+			},
+		},
+		Sitemaster: &snapshotpb.Sitemaster{
+			Timestamp:        timestamp(metrics.Sitemaster.Timestamp),
+			Status:           metrics.Sitemaster.Status,
+			Running:          metrics.Sitemaster.Running,
+			ConnectedToTesla: metrics.Sitemaster.ConnectedToTesla,
+			PowerSupplyMode:  metrics.Sitemaster.PowerSupplyMode,
+			CanReboot:        metrics.Sitemaster.CanReboot,
+		},
+		Status: &snapshotpb.Status{
+			Timestamp:       timestamp(metrics.Status.Timestamp),
+			Din:             metrics.Status.GatewayID,
+			StartTime:       timestamp(metrics.Status.StartTime),
+			Uptime:          durationpb.New(metrics.Status.Uptime),
+			IsNew:           metrics.Status.IsNew,
+			Version:         metrics.Status.FirmwareVersion,
+			GitHash:         metrics.Status.FirmwareGitHash,
+			CommissionCount: int64(metrics.Status.CommissionCount),
+			DeviceType:      metrics.Status.DeviceType,
+			SyncType:        metrics.Status.SyncType,
+			Leader:          c.value(metrics.Status.Leader),
+			Followers:       c.value(metrics.Status.Followers),
+		},
+		NetworkConnectionTests: &snapshotpb.NetworkConnectionTests{
+			Timestamp:  timestamp(metrics.NetworkConnectionTests.Timestamp),
+			Name:       metrics.NetworkConnectionTests.Name,
+			Category:   metrics.NetworkConnectionTests.Category,
+			Disruptive: metrics.NetworkConnectionTests.Disruptive,
+			Inputs:     c.value(metrics.NetworkConnectionTests.Inputs),
+			Alert:      metrics.NetworkConnectionTests.Alert,
+		},
+		SystemTesting: &snapshotpb.SystemTesting{
+			Timestamp:       timestamp(metrics.SystemTesting.Timestamp),
+			Running:         metrics.SystemTesting.Running,
+			Status:          metrics.SystemTesting.Status,
+			ChargeTests:     c.value(metrics.SystemTesting.ChargeTests),
+			MeterResults:    c.value(metrics.SystemTesting.MeterResults),
+			InverterResults: c.value(metrics.SystemTesting.InverterResults),
+			Hysteresis:      int64(metrics.SystemTesting.Hysteresis),
+			Error:           metrics.SystemTesting.Error,
+			Errors:          c.value(metrics.SystemTesting.Errors),
+			Tests:           c.value(metrics.SystemTesting.Tests),
+		},
+		UpdateStatus: &snapshotpb.UpdateStatus{
+			Timestamp:               timestamp(metrics.UpdateStatus.Timestamp),
+			State:                   metrics.UpdateStatus.State,
+			InfoStatus:              metrics.UpdateStatus.Info.Status,
+			CurrentTime:             int64(metrics.UpdateStatus.CurrentTime),
+			LastStatusTime:          int64(metrics.UpdateStatus.LastStatusTime),
+			Version:                 metrics.UpdateStatus.FirmwareVersion,
+			OfflineUpdating:         metrics.UpdateStatus.OfflineUpdating,
+			OfflineUpdateError:      metrics.UpdateStatus.OfflineUpdateError,
+			EstimatedBytesPerSecond: c.value(metrics.UpdateStatus.EstimatedBytesPerSecond),
+		},
+		SystemStatus: c.systemStatus(metrics.SystemStatus),
+		SystemGridStatus: &snapshotpb.SystemGridStatus{
+			Timestamp:          timestamp(metrics.SystemGridStatus.Timestamp),
+			GridStatus:         metrics.SystemGridStatus.GridStatus,
+			GridServicesActive: metrics.SystemGridStatus.GridServicesActive,
+		},
+		SystemStateOfEnergy: &snapshotpb.SystemStateOfEnergy{
+			Timestamp:  timestamp(metrics.SystemStateOfEnergy.Timestamp),
+			Percentage: metrics.SystemStateOfEnergy.Percentage,
+		},
+	}
+
+	for _, solar := range metrics.Solars {
+		teg.Solars = append(teg.Solars, &snapshotpb.Solar{
+			Timestamp:        timestamp(solar.Timestamp),
+			Brand:            solar.Brand,
+			Model:            solar.Model,
+			PowerRatingWatts: int64(solar.PowerRatingWatts),
+		})
+	}
+
+	for _, check := range metrics.NetworkConnectionTests.Checks {
+		teg.NetworkConnectionTests.Checks = append(teg.NetworkConnectionTests.Checks, &snapshotpb.NetworkConnectionCheck{
+			Name:      check.Name,
+			Status:    check.Status,
+			StartTime: timestamp(check.StartTime),
+			EndTime:   timestamp(check.EndTime),
+			Results:   c.value(check.Results),
+			Debug:     c.value(check.Debug),
+			Checks:    c.value(check.Checks),
+		})
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &snapshotpb.Snapshot{
+		Version:   SchemaVersion,
+		GatewayId: metrics.Status.GatewayID,
+		Timestamp: timestamp(metrics.Meters.Timestamp),
+		Teg:       teg,
+	}, nil
+}
+
+// converter keeps the first error from converting the untyped fields, so the
+// conversion reads as one literal
+type converter struct {
+	err error
+}
+
+func (c *converter) value(v interface{}) *structpb.Value {
+	if v == nil {
+		return nil
+	}
+	value, err := structpb.NewValue(v)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return value
+}
+
+func (c *converter) powerwalls(r model.TegPowerwalls) *snapshotpb.Powerwalls {
+	powerwalls := &snapshotpb.Powerwalls{
+		Timestamp: timestamp(r.Timestamp),
+		Sync: &snapshotpb.PowerwallsSync{
+			Updating:                r.Sync.Updating,
+			CommissioningDiagnostic: c.diagnostic(r.Sync.CommissioningDiagnostic),
+			UpdateDiagnostic:        c.diagnostic(r.Sync.UpdateDiagnostic),
+		},
+		Msa:                        c.value(r.Msa),
+		GatewayDin:                 r.GatewayID,
+		PhaseDetectionLastError:    r.PhaseDetectionLastError,
+		OnGridCheckError:           r.OnGridCheckError,
+		States:                     c.value(r.States),
+		BubbleShedding:             r.BubbleShedding,
+		GridCodeValidating:         r.GridCodeValidating,
+		PhaseDetectionNotAvailable: r.PhaseDetectionNotAvailable,
+		RunningPhaseDetection:      r.RunningPhaseDetection,
+		CheckingIfOffgrid:          r.CheckingIfOffgrid,
+		Updating:                   r.Updating,
+		Enumerating:                r.Enumerating,
+		GridQualifying:             r.GridQualifying,
+	}
+
+	for _, pw := range r.Powerwalls {
+		powerwalls.Powerwalls = append(powerwalls.Powerwalls, &snapshotpb.Powerwall{
+			CommissioningDiagnostic:     c.diagnostic(pw.CommissioningDiagnostic),
+			UpdateDiagnostic:            c.diagnostic(pw.UpdateDiagnostic),
+			Type:                        pw.Type,
+			PackagePartNumber:           pw.PackagePartNumber,
+			GridState:                   pw.GridState,
+			PackageSerialNumber:         pw.PackageSerialNumber,
+			Subtype:                     pw.Subtype,
+			BcType:                      c.value(pw.BcType),
+			GridReconnectionTimeSeconds: int64(pw.GridReconnectionTimeSeconds),
+			UnderPhaseDetection:         pw.UnderPhaseDetection,
+			Updating:                    pw.Updating,
+			InConfig:                    pw.InConfig,
+		})
+	}
+
+	return powerwalls
+}
+
+func (c *converter) diagnostic(r model.TegPowerwallDiagnostic) *snapshotpb.PowerwallDiagnostic {
+	diagnostic := &snapshotpb.PowerwallDiagnostic{
+		Name:       r.Name,
+		Category:   r.Category,
+		Inputs:     c.value(r.Inputs),
+		Disruptive: r.Disruptive,
+		Alert:      r.Alert,
+	}
+
+	for _, check := range r.Checks {
+		diagnostic.Checks = append(diagnostic.Checks, &snapshotpb.PowerwallCheck{
+			Name:      check.Name,
+			Status:    check.Status,
+			StartTime: timestamp(check.StartTime),
+			EndTime:   timestamp(check.EndTime),
+			Message:   check.Message,
+			Progress:  int64(check.Progress),
+			Results:   c.value(check.Results),
+			Debug:     c.value(check.Debug),
+			Checks:    c.value(check.Checks),
+		})
+	}
+
+	return diagnostic
+}
+
+func (c *converter) systemStatus(r model.TegSystemStatus) *snapshotpb.SystemStatus {
+	status := &snapshotpb.SystemStatus{
+		Timestamp:                      timestamp(r.Timestamp),
+		CommandSource:                  r.CommandSource,
+		BatteryTargetPower:             r.BatteryTargetPower,
+		BatteryTargetReactivePower:     int64(r.BatteryTargetReactivePower),
+		NominalFullPackEnergy:          int64(r.NominalFullPackEnergyWattHours),
+		NominalEnergyRemaining:         int64(r.NominalEnergyRemainingWattHours),
+		MaxPowerEnergyRemaining:        int64(r.MaxPowerEnergyRemaining),
+		MaxPowerEnergyToBeCharged:      int64(r.MaxPowerEnergyToBeCharged),
+		MaxChargePower:                 int64(r.MaxChargePowerWatts),
+		MaxDischargePower:              r.MaxDischargePowerWatts,
+		MaxApparentPower:               int64(r.MaxApparentPower),
+		InstantaneousMaxDischargePower: int64(r.InstantaneousMaxDischargePower),
+		InstantaneousMaxChargePower:    int64(r.InstantaneousMaxChargePower),
+		GridServicesPower:              r.GridServicesPower,
+		SystemIslandState:              r.SystemIslandState,
+		AvailableBlocks:                int64(r.AvailableBlocks),
+		FfrPowerAvailabilityHigh:       r.FfrPowerAvailabilityHigh,
+		FfrPowerAvailabilityLow:        r.FfrPowerAvailabilityLow,
+		LoadChargeConstraint:           int64(r.LoadChargeConstraint),
+		MaxSustainedRampRate:           int64(r.MaxSustainedRampRate),
+		CanReboot:                      r.CanReboot,
+		SmartInvDeltaP:                 int64(r.SmartInvDeltaP),
+		SmartInvDeltaQ:                 int64(r.SmartInvDeltaQ),
+		Updating:                       r.Updating,
+		LastToggleTimestamp:            timestamp(r.LastToggleTimestamp),
+		SolarRealPowerLimit:            r.SolarRealPowerLimit,
+		Score:                          int64(r.Score),
+		BlocksControlled:               int64(r.BlocksControlled),
+		Primary:                        r.Primary,
+		AuxiliaryLoad:                  int64(r.AuxiliaryLoad),
+		AllEnableLinesHigh:             r.AllEnableLinesHigh,
+		InverterNominalUsablePower:     int64(r.InverterNominalUsablePowerWatts),
+		ExpectedEnergyRemaining:        int64(r.ExpectedEnergyRemaining),
+	}
+
+	for _, block := range r.BatteryBlocks {
+		status.BatteryBlocks = append(status.BatteryBlocks, &snapshotpb.BatteryBlock{
+			Type:                   block.Type,
+			PackagePartNumber:      block.PackagePartNumber,
+			PackageSerialNumber:    block.PackageSerialNumber,
+			DisabledReasons:        block.DisabledReasons,
+			PinvState:              block.PinvState,
+			PinvGridState:          block.PinvGridState,
+			NominalEnergyRemaining: int64(block.NominalEnergyRemainingWattHours),
+			NominalFullPackEnergy:  int64(block.NominalFullPackEnergy),
+			POut:                   block.POut,
+			QOut:                   block.QOut,
+			VOut:                   block.VOut,
+			FOut:                   block.FOut,
+			IOut:                   block.IOut,
+			EnergyCharged:          int64(block.EnergyCharged),
+			EnergyDischarged:       int64(block.EnergyDischarged),
+			OffGrid:                block.OffGrid,
+			VfMode:                 block.VfMode,
+			WobbleDetected:         block.WobbleDetected,
+			ChargePowerClamped:     block.ChargePowerClamped,
+			BackupReady:            block.BackupReady,
+			OpSeqState:             block.OpSeqState,
+			Version:                block.Version,
+		})
+	}
+
+	for _, fault := range r.GridFaults {
+		gridFault := &snapshotpb.GridFault{
+			Timestamp:              int64(fault.Timestamp),
+			AlertName:              fault.AlertName,
+			AlertIsFault:           fault.AlertIsFault,
+			AlertRaw:               int64(fault.AlertRaw),
+			GitHash:                fault.FirmwareGitHash,
+			SiteUid:                fault.SiteUID,
+			EcuType:                fault.EcuType,
+			EcuPackagePartNumber:   fault.EcuPackagePartNumber,
+			EcuPackageSerialNumber: fault.EcuPackageSerialNumber,
+		}
+		for _, alert := range fault.DecodedAlert {
+			gridFault.DecodedAlert = append(gridFault.DecodedAlert, &snapshotpb.GridAlert{
+				Name:  alert.Name,
+				Value: c.value(alert.Value),
+				Units: alert.Units,
+			})
+		}
+		status.GridFaults = append(status.GridFaults, gridFault)
+	}
+
+	return status
+}
+
+func meter(r model.TegMetersAggregate) *snapshotpb.MetersAggregate {
+	return &snapshotpb.MetersAggregate{
+		LastCommunicationTime:             timestamp(r.LastCommunicationTime),
+		InstantPower:                      r.InstantPowerWatts,
+		InstantReactivePower:              r.InstantReactivePowerWatts,
+		InstantApparentPower:              r.InstantApparentPowerWatts,
+		Frequency:                         r.Frequency,
+		EnergyExported:                    r.EnergyExportedWatts,
+		EnergyImported:                    r.EnergyImportedWatts,
+		InstantAverageVoltage:             r.InstantAverageVoltage,
+		InstantAverageCurrent:             r.InstantAverageCurrent,
+		IACurrent:                         r.IACurrent,
+		IBCurrent:                         r.IBCurrent,
+		ICCurrent:                         r.ICCurrent,
+		LastPhaseVoltageCommunicationTime: r.LastPhaseVoltageCommunicationTime,
+		LastPhasePowerCommunicationTime:   r.LastPhasePowerCommunicationTime,
+		Timeout:                           int64(r.Timeout),
+		NumMetersAggregated:               int64(r.NumMetersAggregated),
+		InstantTotalCurrent:               r.InstantTotalCurrent,
+	}
+}
+
+// timestamp leaves times the gateway did not report unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/stream/snapshotpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEncodeProtobuf(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	var metrics model.Teg
+	metrics.Status.GatewayID = "1232100-00-E--TG0123456789AB"
+	metrics.Status.Uptime = 90 * time.Minute
+	metrics.Meters.Timestamp = now
+	metrics.Meters.Site.EnergyImportedWatts = 12345678
+	metrics.SystemStatus.GridFaults = []model.TegGridFault{{
+		AlertName:    "PINV_a008_vfCheckRocof",
+		DecodedAlert: []model.TegGridAlert{{Name: "PINV_alertType", Value: "Warning"}, {Name: "PINV_a008_frequency", Value: 60.1, Units: "Hz"}},
+	}}
+
+	s := &Stream{conf: config.Stream{Format: "protobuf"}}
+	value, err := s.encode(metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &snapshotpb.Snapshot{}
+	err = proto.Unmarshal(value, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.GetVersion() != SchemaVersion || msg.GetGatewayId() != metrics.Status.GatewayID || !msg.GetTimestamp().AsTime().Equal(now) {
+		t.Errorf("unexpected envelope %v", msg)
+	}
+	teg := msg.GetTeg()
+	if teg.GetMeters().GetSite().GetEnergyImported() != 12345678 || teg.GetStatus().GetUptime().AsDuration() != 90*time.Minute {
+		t.Errorf("unexpected snapshot %v", teg)
+	}
+	alerts := teg.GetSystemStatus().GetGridFaults()[0].GetDecodedAlert()
+	if alerts[0].GetValue().GetStringValue() != "Warning" || alerts[1].GetValue().GetNumberValue() != 60.1 {
+		t.Errorf("unexpected decoded alert %v", alerts)
+	}
+	if teg.GetSystemStatus().GetLastToggleTimestamp() != nil {
+		t.Errorf("expected an unreported time to be unset")
+	}
+}
@@ -0,0 +1,3900 @@
+// Snapshot envelope published by the stream package (snapshot.proto)
+//
+// Create snapshot.pb.go for use in projects using the protoc compiler:
+//     protoc --go_out=. --go_opt=paths=source_relative snapshot.proto
+//
+// Fields the gateway reports without a fixed shape are carried as
+// google.protobuf.Value; everything else is typed.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
+// source: snapshot.proto
+
+package snapshotpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Snapshot is the message published for each poll
+type Snapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version is bumped whenever the message changes incompatibly
+	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	GatewayId string `protobuf:"bytes,2,opt,name=gateway_id,json=gatewayId,proto3" json:"gateway_id,omitempty"`
+	// timestamp is when the meters were polled
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Teg           *Teg                   `protobuf:"bytes,4,opt,name=teg,proto3" json:"teg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_snapshot_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Snapshot) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Snapshot) GetGatewayId() string {
+	if x != nil {
+		return x.GatewayId
+	}
+	return ""
+}
+
+func (x *Snapshot) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Snapshot) GetTeg() *Teg {
+	if x != nil {
+		return x.Teg
+	}
+	return nil
+}
+
+// Teg is the aggregated metrics of one poll
+type Teg struct {
+	state                  protoimpl.MessageState  `protogen:"open.v1"`
+	Meters                 *Meters                 `protobuf:"bytes,1,opt,name=meters,proto3" json:"meters,omitempty"`
+	MetersStatus           *MetersStatus           `protobuf:"bytes,2,opt,name=meters_status,json=metersStatus,proto3" json:"meters_status,omitempty"`
+	Operation              *Operation              `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	Powerwalls             *Powerwalls             `protobuf:"bytes,4,opt,name=powerwalls,proto3" json:"powerwalls,omitempty"`
+	SiteInfo               *SiteInfo               `protobuf:"bytes,5,opt,name=site_info,json=siteInfo,proto3" json:"site_info,omitempty"`
+	Sitemaster             *Sitemaster             `protobuf:"bytes,6,opt,name=sitemaster,proto3" json:"sitemaster,omitempty"`
+	Solars                 []*Solar                `protobuf:"bytes,7,rep,name=solars,proto3" json:"solars,omitempty"`
+	Status                 *Status                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	NetworkConnectionTests *NetworkConnectionTests `protobuf:"bytes,9,opt,name=network_connection_tests,json=networkConnectionTests,proto3" json:"network_connection_tests,omitempty"`
+	SystemTesting          *SystemTesting          `protobuf:"bytes,10,opt,name=system_testing,json=systemTesting,proto3" json:"system_testing,omitempty"`
+	UpdateStatus           *UpdateStatus           `protobuf:"bytes,11,opt,name=update_status,json=updateStatus,proto3" json:"update_status,omitempty"`
+	SystemStatus           *SystemStatus           `protobuf:"bytes,12,opt,name=system_status,json=systemStatus,proto3" json:"system_status,omitempty"`
+	SystemGridStatus       *SystemGridStatus       `protobuf:"bytes,13,opt,name=system_grid_status,json=systemGridStatus,proto3" json:"system_grid_status,omitempty"`
+	SystemStateOfEnergy    *SystemStateOfEnergy    `protobuf:"bytes,14,opt,name=system_state_of_energy,json=systemStateOfEnergy,proto3" json:"system_state_of_energy,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Teg) Reset() {
+	*x = Teg{}
+	mi := &file_snapshot_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Teg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Teg) ProtoMessage() {}
+
+func (x *Teg) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Teg.ProtoReflect.Descriptor instead.
+func (*Teg) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Teg) GetMeters() *Meters {
+	if x != nil {
+		return x.Meters
+	}
+	return nil
+}
+
+func (x *Teg) GetMetersStatus() *MetersStatus {
+	if x != nil {
+		return x.MetersStatus
+	}
+	return nil
+}
+
+func (x *Teg) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *Teg) GetPowerwalls() *Powerwalls {
+	if x != nil {
+		return x.Powerwalls
+	}
+	return nil
+}
+
+func (x *Teg) GetSiteInfo() *SiteInfo {
+	if x != nil {
+		return x.SiteInfo
+	}
+	return nil
+}
+
+func (x *Teg) GetSitemaster() *Sitemaster {
+	if x != nil {
+		return x.Sitemaster
+	}
+	return nil
+}
+
+func (x *Teg) GetSolars() []*Solar {
+	if x != nil {
+		return x.Solars
+	}
+	return nil
+}
+
+func (x *Teg) GetStatus() *Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *Teg) GetNetworkConnectionTests() *NetworkConnectionTests {
+	if x != nil {
+		return x.NetworkConnectionTests
+	}
+	return nil
+}
+
+func (x *Teg) GetSystemTesting() *SystemTesting {
+	if x != nil {
+		return x.SystemTesting
+	}
+	return nil
+}
+
+func (x *Teg) GetUpdateStatus() *UpdateStatus {
+	if x != nil {
+		return x.UpdateStatus
+	}
+	return nil
+}
+
+func (x *Teg) GetSystemStatus() *SystemStatus {
+	if x != nil {
+		return x.SystemStatus
+	}
+	return nil
+}
+
+func (x *Teg) GetSystemGridStatus() *SystemGridStatus {
+	if x != nil {
+		return x.SystemGridStatus
+	}
+	return nil
+}
+
+func (x *Teg) GetSystemStateOfEnergy() *SystemStateOfEnergy {
+	if x != nil {
+		return x.SystemStateOfEnergy
+	}
+	return nil
+}
+
+// Meters is /api/meters/aggregates
+type Meters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Site          *MetersAggregate       `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`
+	Battery       *MetersAggregate       `protobuf:"bytes,3,opt,name=battery,proto3" json:"battery,omitempty"`
+	Load          *MetersAggregate       `protobuf:"bytes,4,opt,name=load,proto3" json:"load,omitempty"`
+	Solar         *MetersAggregate       `protobuf:"bytes,5,opt,name=solar,proto3" json:"solar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Meters) Reset() {
+	*x = Meters{}
+	mi := &file_snapshot_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Meters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Meters) ProtoMessage() {}
+
+func (x *Meters) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Meters.ProtoReflect.Descriptor instead.
+func (*Meters) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Meters) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Meters) GetSite() *MetersAggregate {
+	if x != nil {
+		return x.Site
+	}
+	return nil
+}
+
+func (x *Meters) GetBattery() *MetersAggregate {
+	if x != nil {
+		return x.Battery
+	}
+	return nil
+}
+
+func (x *Meters) GetLoad() *MetersAggregate {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+func (x *Meters) GetSolar() *MetersAggregate {
+	if x != nil {
+		return x.Solar
+	}
+	return nil
+}
+
+// MetersAggregate is one meter; energies are lifetime counters in Wh
+type MetersAggregate struct {
+	state                             protoimpl.MessageState `protogen:"open.v1"`
+	LastCommunicationTime             *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=last_communication_time,json=lastCommunicationTime,proto3" json:"last_communication_time,omitempty"`
+	InstantPower                      float64                `protobuf:"fixed64,2,opt,name=instant_power,json=instantPower,proto3" json:"instant_power,omitempty"`
+	InstantReactivePower              float64                `protobuf:"fixed64,3,opt,name=instant_reactive_power,json=instantReactivePower,proto3" json:"instant_reactive_power,omitempty"`
+	InstantApparentPower              float64                `protobuf:"fixed64,4,opt,name=instant_apparent_power,json=instantApparentPower,proto3" json:"instant_apparent_power,omitempty"`
+	Frequency                         float64                `protobuf:"fixed64,5,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	EnergyExported                    float64                `protobuf:"fixed64,6,opt,name=energy_exported,json=energyExported,proto3" json:"energy_exported,omitempty"`
+	EnergyImported                    float64                `protobuf:"fixed64,7,opt,name=energy_imported,json=energyImported,proto3" json:"energy_imported,omitempty"`
+	InstantAverageVoltage             float64                `protobuf:"fixed64,8,opt,name=instant_average_voltage,json=instantAverageVoltage,proto3" json:"instant_average_voltage,omitempty"`
+	InstantAverageCurrent             float64                `protobuf:"fixed64,9,opt,name=instant_average_current,json=instantAverageCurrent,proto3" json:"instant_average_current,omitempty"`
+	IACurrent                         float64                `protobuf:"fixed64,10,opt,name=i_a_current,json=iACurrent,proto3" json:"i_a_current,omitempty"`
+	IBCurrent                         float64                `protobuf:"fixed64,11,opt,name=i_b_current,json=iBCurrent,proto3" json:"i_b_current,omitempty"`
+	ICCurrent                         float64                `protobuf:"fixed64,12,opt,name=i_c_current,json=iCCurrent,proto3" json:"i_c_current,omitempty"`
+	LastPhaseVoltageCommunicationTime string                 `protobuf:"bytes,13,opt,name=last_phase_voltage_communication_time,json=lastPhaseVoltageCommunicationTime,proto3" json:"last_phase_voltage_communication_time,omitempty"`
+	LastPhasePowerCommunicationTime   string                 `protobuf:"bytes,14,opt,name=last_phase_power_communication_time,json=lastPhasePowerCommunicationTime,proto3" json:"last_phase_power_communication_time,omitempty"`
+	Timeout                           int64                  `protobuf:"varint,15,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	NumMetersAggregated               int64                  `protobuf:"varint,16,opt,name=num_meters_aggregated,json=numMetersAggregated,proto3" json:"num_meters_aggregated,omitempty"`
+	InstantTotalCurrent               float64                `protobuf:"fixed64,17,opt,name=instant_total_current,json=instantTotalCurrent,proto3" json:"instant_total_current,omitempty"`
+	unknownFields                     protoimpl.UnknownFields
+	sizeCache                         protoimpl.SizeCache
+}
+
+func (x *MetersAggregate) Reset() {
+	*x = MetersAggregate{}
+	mi := &file_snapshot_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetersAggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetersAggregate) ProtoMessage() {}
+
+func (x *MetersAggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetersAggregate.ProtoReflect.Descriptor instead.
+func (*MetersAggregate) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MetersAggregate) GetLastCommunicationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCommunicationTime
+	}
+	return nil
+}
+
+func (x *MetersAggregate) GetInstantPower() float64 {
+	if x != nil {
+		return x.InstantPower
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetInstantReactivePower() float64 {
+	if x != nil {
+		return x.InstantReactivePower
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetInstantApparentPower() float64 {
+	if x != nil {
+		return x.InstantApparentPower
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetFrequency() float64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetEnergyExported() float64 {
+	if x != nil {
+		return x.EnergyExported
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetEnergyImported() float64 {
+	if x != nil {
+		return x.EnergyImported
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetInstantAverageVoltage() float64 {
+	if x != nil {
+		return x.InstantAverageVoltage
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetInstantAverageCurrent() float64 {
+	if x != nil {
+		return x.InstantAverageCurrent
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetIACurrent() float64 {
+	if x != nil {
+		return x.IACurrent
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetIBCurrent() float64 {
+	if x != nil {
+		return x.IBCurrent
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetICCurrent() float64 {
+	if x != nil {
+		return x.ICCurrent
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetLastPhaseVoltageCommunicationTime() string {
+	if x != nil {
+		return x.LastPhaseVoltageCommunicationTime
+	}
+	return ""
+}
+
+func (x *MetersAggregate) GetLastPhasePowerCommunicationTime() string {
+	if x != nil {
+		return x.LastPhasePowerCommunicationTime
+	}
+	return ""
+}
+
+func (x *MetersAggregate) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetNumMetersAggregated() int64 {
+	if x != nil {
+		return x.NumMetersAggregated
+	}
+	return 0
+}
+
+func (x *MetersAggregate) GetInstantTotalCurrent() float64 {
+	if x != nil {
+		return x.InstantTotalCurrent
+	}
+	return 0
+}
+
+// MetersStatus is /api/meters/status
+type MetersStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Errors        *structpb.Value        `protobuf:"bytes,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	Serial        string                 `protobuf:"bytes,4,opt,name=serial,proto3" json:"serial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetersStatus) Reset() {
+	*x = MetersStatus{}
+	mi := &file_snapshot_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetersStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetersStatus) ProtoMessage() {}
+
+func (x *MetersStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetersStatus.ProtoReflect.Descriptor instead.
+func (*MetersStatus) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MetersStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *MetersStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MetersStatus) GetErrors() *structpb.Value {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *MetersStatus) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+// Operation is /api/operation
+type Operation struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	RealMode                string                 `protobuf:"bytes,2,opt,name=real_mode,json=realMode,proto3" json:"real_mode,omitempty"`
+	BackupReservePercent    float64                `protobuf:"fixed64,3,opt,name=backup_reserve_percent,json=backupReservePercent,proto3" json:"backup_reserve_percent,omitempty"`
+	FreqShiftLoadShedSoe    int64                  `protobuf:"varint,4,opt,name=freq_shift_load_shed_soe,json=freqShiftLoadShedSoe,proto3" json:"freq_shift_load_shed_soe,omitempty"`
+	FreqShiftLoadShedDeltaF float64                `protobuf:"fixed64,5,opt,name=freq_shift_load_shed_delta_f,json=freqShiftLoadShedDeltaF,proto3" json:"freq_shift_load_shed_delta_f,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_snapshot_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Operation) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Operation) GetRealMode() string {
+	if x != nil {
+		return x.RealMode
+	}
+	return ""
+}
+
+func (x *Operation) GetBackupReservePercent() float64 {
+	if x != nil {
+		return x.BackupReservePercent
+	}
+	return 0
+}
+
+func (x *Operation) GetFreqShiftLoadShedSoe() int64 {
+	if x != nil {
+		return x.FreqShiftLoadShedSoe
+	}
+	return 0
+}
+
+func (x *Operation) GetFreqShiftLoadShedDeltaF() float64 {
+	if x != nil {
+		return x.FreqShiftLoadShedDeltaF
+	}
+	return 0
+}
+
+// Powerwalls is /api/powerwalls
+type Powerwalls struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp                  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Sync                       *PowerwallsSync        `protobuf:"bytes,2,opt,name=sync,proto3" json:"sync,omitempty"`
+	Powerwalls                 []*Powerwall           `protobuf:"bytes,3,rep,name=powerwalls,proto3" json:"powerwalls,omitempty"`
+	Msa                        *structpb.Value        `protobuf:"bytes,4,opt,name=msa,proto3" json:"msa,omitempty"`
+	GatewayDin                 string                 `protobuf:"bytes,5,opt,name=gateway_din,json=gatewayDin,proto3" json:"gateway_din,omitempty"`
+	PhaseDetectionLastError    string                 `protobuf:"bytes,6,opt,name=phase_detection_last_error,json=phaseDetectionLastError,proto3" json:"phase_detection_last_error,omitempty"`
+	OnGridCheckError           string                 `protobuf:"bytes,7,opt,name=on_grid_check_error,json=onGridCheckError,proto3" json:"on_grid_check_error,omitempty"`
+	States                     *structpb.Value        `protobuf:"bytes,8,opt,name=states,proto3" json:"states,omitempty"`
+	BubbleShedding             bool                   `protobuf:"varint,9,opt,name=bubble_shedding,json=bubbleShedding,proto3" json:"bubble_shedding,omitempty"`
+	GridCodeValidating         bool                   `protobuf:"varint,10,opt,name=grid_code_validating,json=gridCodeValidating,proto3" json:"grid_code_validating,omitempty"`
+	PhaseDetectionNotAvailable bool                   `protobuf:"varint,11,opt,name=phase_detection_not_available,json=phaseDetectionNotAvailable,proto3" json:"phase_detection_not_available,omitempty"`
+	RunningPhaseDetection      bool                   `protobuf:"varint,12,opt,name=running_phase_detection,json=runningPhaseDetection,proto3" json:"running_phase_detection,omitempty"`
+	CheckingIfOffgrid          bool                   `protobuf:"varint,13,opt,name=checking_if_offgrid,json=checkingIfOffgrid,proto3" json:"checking_if_offgrid,omitempty"`
+	Updating                   bool                   `protobuf:"varint,14,opt,name=updating,proto3" json:"updating,omitempty"`
+	Enumerating                bool                   `protobuf:"varint,15,opt,name=enumerating,proto3" json:"enumerating,omitempty"`
+	GridQualifying             bool                   `protobuf:"varint,16,opt,name=grid_qualifying,json=gridQualifying,proto3" json:"grid_qualifying,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *Powerwalls) Reset() {
+	*x = Powerwalls{}
+	mi := &file_snapshot_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Powerwalls) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Powerwalls) ProtoMessage() {}
+
+func (x *Powerwalls) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Powerwalls.ProtoReflect.Descriptor instead.
+func (*Powerwalls) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Powerwalls) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Powerwalls) GetSync() *PowerwallsSync {
+	if x != nil {
+		return x.Sync
+	}
+	return nil
+}
+
+func (x *Powerwalls) GetPowerwalls() []*Powerwall {
+	if x != nil {
+		return x.Powerwalls
+	}
+	return nil
+}
+
+func (x *Powerwalls) GetMsa() *structpb.Value {
+	if x != nil {
+		return x.Msa
+	}
+	return nil
+}
+
+func (x *Powerwalls) GetGatewayDin() string {
+	if x != nil {
+		return x.GatewayDin
+	}
+	return ""
+}
+
+func (x *Powerwalls) GetPhaseDetectionLastError() string {
+	if x != nil {
+		return x.PhaseDetectionLastError
+	}
+	return ""
+}
+
+func (x *Powerwalls) GetOnGridCheckError() string {
+	if x != nil {
+		return x.OnGridCheckError
+	}
+	return ""
+}
+
+func (x *Powerwalls) GetStates() *structpb.Value {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *Powerwalls) GetBubbleShedding() bool {
+	if x != nil {
+		return x.BubbleShedding
+	}
+	return false
+}
+
+func (x *Powerwalls) GetGridCodeValidating() bool {
+	if x != nil {
+		return x.GridCodeValidating
+	}
+	return false
+}
+
+func (x *Powerwalls) GetPhaseDetectionNotAvailable() bool {
+	if x != nil {
+		return x.PhaseDetectionNotAvailable
+	}
+	return false
+}
+
+func (x *Powerwalls) GetRunningPhaseDetection() bool {
+	if x != nil {
+		return x.RunningPhaseDetection
+	}
+	return false
+}
+
+func (x *Powerwalls) GetCheckingIfOffgrid() bool {
+	if x != nil {
+		return x.CheckingIfOffgrid
+	}
+	return false
+}
+
+func (x *Powerwalls) GetUpdating() bool {
+	if x != nil {
+		return x.Updating
+	}
+	return false
+}
+
+func (x *Powerwalls) GetEnumerating() bool {
+	if x != nil {
+		return x.Enumerating
+	}
+	return false
+}
+
+func (x *Powerwalls) GetGridQualifying() bool {
+	if x != nil {
+		return x.GridQualifying
+	}
+	return false
+}
+
+// Powerwall is one Powerwall of /api/powerwalls
+type Powerwall struct {
+	state                       protoimpl.MessageState `protogen:"open.v1"`
+	CommissioningDiagnostic     *PowerwallDiagnostic   `protobuf:"bytes,1,opt,name=commissioning_diagnostic,json=commissioningDiagnostic,proto3" json:"commissioning_diagnostic,omitempty"`
+	UpdateDiagnostic            *PowerwallDiagnostic   `protobuf:"bytes,2,opt,name=update_diagnostic,json=updateDiagnostic,proto3" json:"update_diagnostic,omitempty"`
+	Type                        string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	PackagePartNumber           string                 `protobuf:"bytes,4,opt,name=package_part_number,json=packagePartNumber,proto3" json:"package_part_number,omitempty"`
+	GridState                   string                 `protobuf:"bytes,5,opt,name=grid_state,json=gridState,proto3" json:"grid_state,omitempty"`
+	PackageSerialNumber         string                 `protobuf:"bytes,6,opt,name=package_serial_number,json=packageSerialNumber,proto3" json:"package_serial_number,omitempty"`
+	Subtype                     string                 `protobuf:"bytes,7,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	BcType                      *structpb.Value        `protobuf:"bytes,8,opt,name=bc_type,json=bcType,proto3" json:"bc_type,omitempty"`
+	GridReconnectionTimeSeconds int64                  `protobuf:"varint,9,opt,name=grid_reconnection_time_seconds,json=gridReconnectionTimeSeconds,proto3" json:"grid_reconnection_time_seconds,omitempty"`
+	UnderPhaseDetection         bool                   `protobuf:"varint,10,opt,name=under_phase_detection,json=underPhaseDetection,proto3" json:"under_phase_detection,omitempty"`
+	Updating                    bool                   `protobuf:"varint,11,opt,name=updating,proto3" json:"updating,omitempty"`
+	InConfig                    bool                   `protobuf:"varint,12,opt,name=in_config,json=inConfig,proto3" json:"in_config,omitempty"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *Powerwall) Reset() {
+	*x = Powerwall{}
+	mi := &file_snapshot_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Powerwall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Powerwall) ProtoMessage() {}
+
+func (x *Powerwall) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Powerwall.ProtoReflect.Descriptor instead.
+func (*Powerwall) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Powerwall) GetCommissioningDiagnostic() *PowerwallDiagnostic {
+	if x != nil {
+		return x.CommissioningDiagnostic
+	}
+	return nil
+}
+
+func (x *Powerwall) GetUpdateDiagnostic() *PowerwallDiagnostic {
+	if x != nil {
+		return x.UpdateDiagnostic
+	}
+	return nil
+}
+
+func (x *Powerwall) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Powerwall) GetPackagePartNumber() string {
+	if x != nil {
+		return x.PackagePartNumber
+	}
+	return ""
+}
+
+func (x *Powerwall) GetGridState() string {
+	if x != nil {
+		return x.GridState
+	}
+	return ""
+}
+
+func (x *Powerwall) GetPackageSerialNumber() string {
+	if x != nil {
+		return x.PackageSerialNumber
+	}
+	return ""
+}
+
+func (x *Powerwall) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *Powerwall) GetBcType() *structpb.Value {
+	if x != nil {
+		return x.BcType
+	}
+	return nil
+}
+
+func (x *Powerwall) GetGridReconnectionTimeSeconds() int64 {
+	if x != nil {
+		return x.GridReconnectionTimeSeconds
+	}
+	return 0
+}
+
+func (x *Powerwall) GetUnderPhaseDetection() bool {
+	if x != nil {
+		return x.UnderPhaseDetection
+	}
+	return false
+}
+
+func (x *Powerwall) GetUpdating() bool {
+	if x != nil {
+		return x.Updating
+	}
+	return false
+}
+
+func (x *Powerwall) GetInConfig() bool {
+	if x != nil {
+		return x.InConfig
+	}
+	return false
+}
+
+// PowerwallsSync is the sync state of /api/powerwalls
+type PowerwallsSync struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Updating                bool                   `protobuf:"varint,1,opt,name=updating,proto3" json:"updating,omitempty"`
+	CommissioningDiagnostic *PowerwallDiagnostic   `protobuf:"bytes,2,opt,name=commissioning_diagnostic,json=commissioningDiagnostic,proto3" json:"commissioning_diagnostic,omitempty"`
+	UpdateDiagnostic        *PowerwallDiagnostic   `protobuf:"bytes,3,opt,name=update_diagnostic,json=updateDiagnostic,proto3" json:"update_diagnostic,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *PowerwallsSync) Reset() {
+	*x = PowerwallsSync{}
+	mi := &file_snapshot_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerwallsSync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerwallsSync) ProtoMessage() {}
+
+func (x *PowerwallsSync) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerwallsSync.ProtoReflect.Descriptor instead.
+func (*PowerwallsSync) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PowerwallsSync) GetUpdating() bool {
+	if x != nil {
+		return x.Updating
+	}
+	return false
+}
+
+func (x *PowerwallsSync) GetCommissioningDiagnostic() *PowerwallDiagnostic {
+	if x != nil {
+		return x.CommissioningDiagnostic
+	}
+	return nil
+}
+
+func (x *PowerwallsSync) GetUpdateDiagnostic() *PowerwallDiagnostic {
+	if x != nil {
+		return x.UpdateDiagnostic
+	}
+	return nil
+}
+
+// PowerwallDiagnostic is a set of diagnostic checks
+type PowerwallDiagnostic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checks        []*PowerwallCheck      `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Inputs        *structpb.Value        `protobuf:"bytes,4,opt,name=inputs,proto3" json:"inputs,omitempty"`
+	Disruptive    bool                   `protobuf:"varint,5,opt,name=disruptive,proto3" json:"disruptive,omitempty"`
+	Alert         bool                   `protobuf:"varint,6,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PowerwallDiagnostic) Reset() {
+	*x = PowerwallDiagnostic{}
+	mi := &file_snapshot_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerwallDiagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerwallDiagnostic) ProtoMessage() {}
+
+func (x *PowerwallDiagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerwallDiagnostic.ProtoReflect.Descriptor instead.
+func (*PowerwallDiagnostic) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PowerwallDiagnostic) GetChecks() []*PowerwallCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *PowerwallDiagnostic) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PowerwallDiagnostic) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *PowerwallDiagnostic) GetInputs() *structpb.Value {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *PowerwallDiagnostic) GetDisruptive() bool {
+	if x != nil {
+		return x.Disruptive
+	}
+	return false
+}
+
+func (x *PowerwallDiagnostic) GetAlert() bool {
+	if x != nil {
+		return x.Alert
+	}
+	return false
+}
+
+// PowerwallCheck is one diagnostic check
+type PowerwallCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Progress      int64                  `protobuf:"varint,6,opt,name=progress,proto3" json:"progress,omitempty"`
+	Results       *structpb.Value        `protobuf:"bytes,7,opt,name=results,proto3" json:"results,omitempty"`
+	Debug         *structpb.Value        `protobuf:"bytes,8,opt,name=debug,proto3" json:"debug,omitempty"`
+	Checks        *structpb.Value        `protobuf:"bytes,9,opt,name=checks,proto3" json:"checks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PowerwallCheck) Reset() {
+	*x = PowerwallCheck{}
+	mi := &file_snapshot_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerwallCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerwallCheck) ProtoMessage() {}
+
+func (x *PowerwallCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerwallCheck.ProtoReflect.Descriptor instead.
+func (*PowerwallCheck) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PowerwallCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PowerwallCheck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PowerwallCheck) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *PowerwallCheck) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *PowerwallCheck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PowerwallCheck) GetProgress() int64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *PowerwallCheck) GetResults() *structpb.Value {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PowerwallCheck) GetDebug() *structpb.Value {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+func (x *PowerwallCheck) GetChecks() *structpb.Value {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+// SiteInfo is /api/site_info
+type SiteInfo struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MeasuredFrequency      float64                `protobuf:"fixed64,2,opt,name=measured_frequency,json=measuredFrequency,proto3" json:"measured_frequency,omitempty"`
+	MaxSystemEnergyKwh     float64                `protobuf:"fixed64,3,opt,name=max_system_energy_kwh,json=maxSystemEnergyKwh,proto3" json:"max_system_energy_kwh,omitempty"`
+	MaxSystemPowerKw       float64                `protobuf:"fixed64,4,opt,name=max_system_power_kw,json=maxSystemPowerKw,proto3" json:"max_system_power_kw,omitempty"`
+	SiteName               string                 `protobuf:"bytes,5,opt,name=site_name,json=siteName,proto3" json:"site_name,omitempty"`
+	Timezone               string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	NetMeterMode           string                 `protobuf:"bytes,7,opt,name=net_meter_mode,json=netMeterMode,proto3" json:"net_meter_mode,omitempty"`
+	MaxSiteMeterPowerKw    int64                  `protobuf:"varint,8,opt,name=max_site_meter_power_kw,json=maxSiteMeterPowerKw,proto3" json:"max_site_meter_power_kw,omitempty"`
+	MinSiteMeterPowerKw    int64                  `protobuf:"varint,9,opt,name=min_site_meter_power_kw,json=minSiteMeterPowerKw,proto3" json:"min_site_meter_power_kw,omitempty"`
+	NominalSystemEnergyKwh float64                `protobuf:"fixed64,10,opt,name=nominal_system_energy_kwh,json=nominalSystemEnergyKwh,proto3" json:"nominal_system_energy_kwh,omitempty"`
+	NominalSystemPowerKw   float64                `protobuf:"fixed64,11,opt,name=nominal_system_power_kw,json=nominalSystemPowerKw,proto3" json:"nominal_system_power_kw,omitempty"`
+	PanelMaxCurrent        int64                  `protobuf:"varint,12,opt,name=panel_max_current,json=panelMaxCurrent,proto3" json:"panel_max_current,omitempty"`
+	GridCode               *GridCode              `protobuf:"bytes,13,opt,name=grid_code,json=gridCode,proto3" json:"grid_code,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SiteInfo) Reset() {
+	*x = SiteInfo{}
+	mi := &file_snapshot_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SiteInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteInfo) ProtoMessage() {}
+
+func (x *SiteInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteInfo.ProtoReflect.Descriptor instead.
+func (*SiteInfo) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SiteInfo) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SiteInfo) GetMeasuredFrequency() float64 {
+	if x != nil {
+		return x.MeasuredFrequency
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetMaxSystemEnergyKwh() float64 {
+	if x != nil {
+		return x.MaxSystemEnergyKwh
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetMaxSystemPowerKw() float64 {
+	if x != nil {
+		return x.MaxSystemPowerKw
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetSiteName() string {
+	if x != nil {
+		return x.SiteName
+	}
+	return ""
+}
+
+func (x *SiteInfo) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *SiteInfo) GetNetMeterMode() string {
+	if x != nil {
+		return x.NetMeterMode
+	}
+	return ""
+}
+
+func (x *SiteInfo) GetMaxSiteMeterPowerKw() int64 {
+	if x != nil {
+		return x.MaxSiteMeterPowerKw
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetMinSiteMeterPowerKw() int64 {
+	if x != nil {
+		return x.MinSiteMeterPowerKw
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetNominalSystemEnergyKwh() float64 {
+	if x != nil {
+		return x.NominalSystemEnergyKwh
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetNominalSystemPowerKw() float64 {
+	if x != nil {
+		return x.NominalSystemPowerKw
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetPanelMaxCurrent() int64 {
+	if x != nil {
+		return x.PanelMaxCurrent
+	}
+	return 0
+}
+
+func (x *SiteInfo) GetGridCode() *GridCode {
+	if x != nil {
+		return x.GridCode
+	}
+	return nil
+}
+
+// GridCode is the grid code of /api/site_info
+type GridCode struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	GridCode           string                 `protobuf:"bytes,1,opt,name=grid_code,json=gridCode,proto3" json:"grid_code,omitempty"`
+	GridVoltageSetting int64                  `protobuf:"varint,2,opt,name=grid_voltage_setting,json=gridVoltageSetting,proto3" json:"grid_voltage_setting,omitempty"`
+	GridFreqSetting    int64                  `protobuf:"varint,3,opt,name=grid_freq_setting,json=gridFreqSetting,proto3" json:"grid_freq_setting,omitempty"`
+	GridPhaseSetting   string                 `protobuf:"bytes,4,opt,name=grid_phase_setting,json=gridPhaseSetting,proto3" json:"grid_phase_setting,omitempty"`
+	Country            string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	State              string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Utility            string                 `protobuf:"bytes,7,opt,name=utility,proto3" json:"utility,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GridCode) Reset() {
+	*x = GridCode{}
+	mi := &file_snapshot_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GridCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GridCode) ProtoMessage() {}
+
+func (x *GridCode) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GridCode.ProtoReflect.Descriptor instead.
+func (*GridCode) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GridCode) GetGridCode() string {
+	if x != nil {
+		return x.GridCode
+	}
+	return ""
+}
+
+func (x *GridCode) GetGridVoltageSetting() int64 {
+	if x != nil {
+		return x.GridVoltageSetting
+	}
+	return 0
+}
+
+func (x *GridCode) GetGridFreqSetting() int64 {
+	if x != nil {
+		return x.GridFreqSetting
+	}
+	return 0
+}
+
+func (x *GridCode) GetGridPhaseSetting() string {
+	if x != nil {
+		return x.GridPhaseSetting
+	}
+	return ""
+}
+
+func (x *GridCode) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *GridCode) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GridCode) GetUtility() string {
+	if x != nil {
+		return x.Utility
+	}
+	return ""
+}
+
+// Sitemaster is /api/sitemaster
+type Sitemaster struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Status           string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Running          bool                   `protobuf:"varint,3,opt,name=running,proto3" json:"running,omitempty"`
+	ConnectedToTesla bool                   `protobuf:"varint,4,opt,name=connected_to_tesla,json=connectedToTesla,proto3" json:"connected_to_tesla,omitempty"`
+	PowerSupplyMode  bool                   `protobuf:"varint,5,opt,name=power_supply_mode,json=powerSupplyMode,proto3" json:"power_supply_mode,omitempty"`
+	CanReboot        string                 `protobuf:"bytes,6,opt,name=can_reboot,json=canReboot,proto3" json:"can_reboot,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Sitemaster) Reset() {
+	*x = Sitemaster{}
+	mi := &file_snapshot_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sitemaster) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sitemaster) ProtoMessage() {}
+
+func (x *Sitemaster) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sitemaster.ProtoReflect.Descriptor instead.
+func (*Sitemaster) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Sitemaster) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Sitemaster) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Sitemaster) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *Sitemaster) GetConnectedToTesla() bool {
+	if x != nil {
+		return x.ConnectedToTesla
+	}
+	return false
+}
+
+func (x *Sitemaster) GetPowerSupplyMode() bool {
+	if x != nil {
+		return x.PowerSupplyMode
+	}
+	return false
+}
+
+func (x *Sitemaster) GetCanReboot() string {
+	if x != nil {
+		return x.CanReboot
+	}
+	return ""
+}
+
+// Solar is one inverter of /api/solars
+type Solar struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Brand            string                 `protobuf:"bytes,2,opt,name=brand,proto3" json:"brand,omitempty"`
+	Model            string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	PowerRatingWatts int64                  `protobuf:"varint,4,opt,name=power_rating_watts,json=powerRatingWatts,proto3" json:"power_rating_watts,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Solar) Reset() {
+	*x = Solar{}
+	mi := &file_snapshot_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Solar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Solar) ProtoMessage() {}
+
+func (x *Solar) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Solar.ProtoReflect.Descriptor instead.
+func (*Solar) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Solar) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Solar) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Solar) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Solar) GetPowerRatingWatts() int64 {
+	if x != nil {
+		return x.PowerRatingWatts
+	}
+	return 0
+}
+
+// Status is /api/status
+type Status struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Din             string                 `protobuf:"bytes,2,opt,name=din,proto3" json:"din,omitempty"`
+	StartTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Uptime          *durationpb.Duration   `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	IsNew           bool                   `protobuf:"varint,5,opt,name=is_new,json=isNew,proto3" json:"is_new,omitempty"`
+	Version         string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	GitHash         string                 `protobuf:"bytes,7,opt,name=git_hash,json=gitHash,proto3" json:"git_hash,omitempty"`
+	CommissionCount int64                  `protobuf:"varint,8,opt,name=commission_count,json=commissionCount,proto3" json:"commission_count,omitempty"`
+	DeviceType      string                 `protobuf:"bytes,9,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	SyncType        string                 `protobuf:"bytes,10,opt,name=sync_type,json=syncType,proto3" json:"sync_type,omitempty"`
+	Leader          *structpb.Value        `protobuf:"bytes,11,opt,name=leader,proto3" json:"leader,omitempty"`
+	Followers       *structpb.Value        `protobuf:"bytes,12,opt,name=followers,proto3" json:"followers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_snapshot_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Status) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Status) GetDin() string {
+	if x != nil {
+		return x.Din
+	}
+	return ""
+}
+
+func (x *Status) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Status) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *Status) GetIsNew() bool {
+	if x != nil {
+		return x.IsNew
+	}
+	return false
+}
+
+func (x *Status) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Status) GetGitHash() string {
+	if x != nil {
+		return x.GitHash
+	}
+	return ""
+}
+
+func (x *Status) GetCommissionCount() int64 {
+	if x != nil {
+		return x.CommissionCount
+	}
+	return 0
+}
+
+func (x *Status) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Status) GetSyncType() string {
+	if x != nil {
+		return x.SyncType
+	}
+	return ""
+}
+
+func (x *Status) GetLeader() *structpb.Value {
+	if x != nil {
+		return x.Leader
+	}
+	return nil
+}
+
+func (x *Status) GetFollowers() *structpb.Value {
+	if x != nil {
+		return x.Followers
+	}
+	return nil
+}
+
+// NetworkConnectionTests is /api/system/networks/conn_tests
+type NetworkConnectionTests struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp    `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Name          string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category      string                    `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Disruptive    bool                      `protobuf:"varint,4,opt,name=disruptive,proto3" json:"disruptive,omitempty"`
+	Inputs        *structpb.Value           `protobuf:"bytes,5,opt,name=inputs,proto3" json:"inputs,omitempty"`
+	Checks        []*NetworkConnectionCheck `protobuf:"bytes,6,rep,name=checks,proto3" json:"checks,omitempty"`
+	Alert         bool                      `protobuf:"varint,7,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkConnectionTests) Reset() {
+	*x = NetworkConnectionTests{}
+	mi := &file_snapshot_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkConnectionTests) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkConnectionTests) ProtoMessage() {}
+
+func (x *NetworkConnectionTests) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkConnectionTests.ProtoReflect.Descriptor instead.
+func (*NetworkConnectionTests) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NetworkConnectionTests) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *NetworkConnectionTests) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkConnectionTests) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *NetworkConnectionTests) GetDisruptive() bool {
+	if x != nil {
+		return x.Disruptive
+	}
+	return false
+}
+
+func (x *NetworkConnectionTests) GetInputs() *structpb.Value {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *NetworkConnectionTests) GetChecks() []*NetworkConnectionCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *NetworkConnectionTests) GetAlert() bool {
+	if x != nil {
+		return x.Alert
+	}
+	return false
+}
+
+// NetworkConnectionCheck is one network connection check
+type NetworkConnectionCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Results       *structpb.Value        `protobuf:"bytes,5,opt,name=results,proto3" json:"results,omitempty"`
+	Debug         *structpb.Value        `protobuf:"bytes,6,opt,name=debug,proto3" json:"debug,omitempty"`
+	Checks        *structpb.Value        `protobuf:"bytes,7,opt,name=checks,proto3" json:"checks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkConnectionCheck) Reset() {
+	*x = NetworkConnectionCheck{}
+	mi := &file_snapshot_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkConnectionCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkConnectionCheck) ProtoMessage() {}
+
+func (x *NetworkConnectionCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkConnectionCheck.ProtoReflect.Descriptor instead.
+func (*NetworkConnectionCheck) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *NetworkConnectionCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkConnectionCheck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *NetworkConnectionCheck) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *NetworkConnectionCheck) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *NetworkConnectionCheck) GetResults() *structpb.Value {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *NetworkConnectionCheck) GetDebug() *structpb.Value {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+func (x *NetworkConnectionCheck) GetChecks() *structpb.Value {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+// SystemTesting is /api/system/testing
+type SystemTesting struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Running         bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	ChargeTests     *structpb.Value        `protobuf:"bytes,4,opt,name=charge_tests,json=chargeTests,proto3" json:"charge_tests,omitempty"`
+	MeterResults    *structpb.Value        `protobuf:"bytes,5,opt,name=meter_results,json=meterResults,proto3" json:"meter_results,omitempty"`
+	InverterResults *structpb.Value        `protobuf:"bytes,6,opt,name=inverter_results,json=inverterResults,proto3" json:"inverter_results,omitempty"`
+	Hysteresis      int64                  `protobuf:"varint,7,opt,name=hysteresis,proto3" json:"hysteresis,omitempty"`
+	Error           string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	Errors          *structpb.Value        `protobuf:"bytes,9,opt,name=errors,proto3" json:"errors,omitempty"`
+	Tests           *structpb.Value        `protobuf:"bytes,10,opt,name=tests,proto3" json:"tests,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SystemTesting) Reset() {
+	*x = SystemTesting{}
+	mi := &file_snapshot_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemTesting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemTesting) ProtoMessage() {}
+
+func (x *SystemTesting) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemTesting.ProtoReflect.Descriptor instead.
+func (*SystemTesting) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SystemTesting) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SystemTesting) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *SystemTesting) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SystemTesting) GetChargeTests() *structpb.Value {
+	if x != nil {
+		return x.ChargeTests
+	}
+	return nil
+}
+
+func (x *SystemTesting) GetMeterResults() *structpb.Value {
+	if x != nil {
+		return x.MeterResults
+	}
+	return nil
+}
+
+func (x *SystemTesting) GetInverterResults() *structpb.Value {
+	if x != nil {
+		return x.InverterResults
+	}
+	return nil
+}
+
+func (x *SystemTesting) GetHysteresis() int64 {
+	if x != nil {
+		return x.Hysteresis
+	}
+	return 0
+}
+
+func (x *SystemTesting) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SystemTesting) GetErrors() *structpb.Value {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *SystemTesting) GetTests() *structpb.Value {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+// UpdateStatus is /api/system/update/status
+type UpdateStatus struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	State                   string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	InfoStatus              []string               `protobuf:"bytes,3,rep,name=info_status,json=infoStatus,proto3" json:"info_status,omitempty"`
+	CurrentTime             int64                  `protobuf:"varint,4,opt,name=current_time,json=currentTime,proto3" json:"current_time,omitempty"`
+	LastStatusTime          int64                  `protobuf:"varint,5,opt,name=last_status_time,json=lastStatusTime,proto3" json:"last_status_time,omitempty"`
+	Version                 string                 `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	OfflineUpdating         bool                   `protobuf:"varint,7,opt,name=offline_updating,json=offlineUpdating,proto3" json:"offline_updating,omitempty"`
+	OfflineUpdateError      string                 `protobuf:"bytes,8,opt,name=offline_update_error,json=offlineUpdateError,proto3" json:"offline_update_error,omitempty"`
+	EstimatedBytesPerSecond *structpb.Value        `protobuf:"bytes,9,opt,name=estimated_bytes_per_second,json=estimatedBytesPerSecond,proto3" json:"estimated_bytes_per_second,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *UpdateStatus) Reset() {
+	*x = UpdateStatus{}
+	mi := &file_snapshot_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatus) ProtoMessage() {}
+
+func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
+func (*UpdateStatus) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *UpdateStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetInfoStatus() []string {
+	if x != nil {
+		return x.InfoStatus
+	}
+	return nil
+}
+
+func (x *UpdateStatus) GetCurrentTime() int64 {
+	if x != nil {
+		return x.CurrentTime
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetLastStatusTime() int64 {
+	if x != nil {
+		return x.LastStatusTime
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetOfflineUpdating() bool {
+	if x != nil {
+		return x.OfflineUpdating
+	}
+	return false
+}
+
+func (x *UpdateStatus) GetOfflineUpdateError() string {
+	if x != nil {
+		return x.OfflineUpdateError
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetEstimatedBytesPerSecond() *structpb.Value {
+	if x != nil {
+		return x.EstimatedBytesPerSecond
+	}
+	return nil
+}
+
+// SystemStatus is /api/system_status
+type SystemStatus struct {
+	state                          protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp                      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CommandSource                  string                 `protobuf:"bytes,2,opt,name=command_source,json=commandSource,proto3" json:"command_source,omitempty"`
+	BatteryTargetPower             float64                `protobuf:"fixed64,3,opt,name=battery_target_power,json=batteryTargetPower,proto3" json:"battery_target_power,omitempty"`
+	BatteryTargetReactivePower     int64                  `protobuf:"varint,4,opt,name=battery_target_reactive_power,json=batteryTargetReactivePower,proto3" json:"battery_target_reactive_power,omitempty"`
+	NominalFullPackEnergy          int64                  `protobuf:"varint,5,opt,name=nominal_full_pack_energy,json=nominalFullPackEnergy,proto3" json:"nominal_full_pack_energy,omitempty"`
+	NominalEnergyRemaining         int64                  `protobuf:"varint,6,opt,name=nominal_energy_remaining,json=nominalEnergyRemaining,proto3" json:"nominal_energy_remaining,omitempty"`
+	MaxPowerEnergyRemaining        int64                  `protobuf:"varint,7,opt,name=max_power_energy_remaining,json=maxPowerEnergyRemaining,proto3" json:"max_power_energy_remaining,omitempty"`
+	MaxPowerEnergyToBeCharged      int64                  `protobuf:"varint,8,opt,name=max_power_energy_to_be_charged,json=maxPowerEnergyToBeCharged,proto3" json:"max_power_energy_to_be_charged,omitempty"`
+	MaxChargePower                 int64                  `protobuf:"varint,9,opt,name=max_charge_power,json=maxChargePower,proto3" json:"max_charge_power,omitempty"`
+	MaxDischargePower              float64                `protobuf:"fixed64,10,opt,name=max_discharge_power,json=maxDischargePower,proto3" json:"max_discharge_power,omitempty"`
+	MaxApparentPower               int64                  `protobuf:"varint,11,opt,name=max_apparent_power,json=maxApparentPower,proto3" json:"max_apparent_power,omitempty"`
+	InstantaneousMaxDischargePower int64                  `protobuf:"varint,12,opt,name=instantaneous_max_discharge_power,json=instantaneousMaxDischargePower,proto3" json:"instantaneous_max_discharge_power,omitempty"`
+	InstantaneousMaxChargePower    int64                  `protobuf:"varint,13,opt,name=instantaneous_max_charge_power,json=instantaneousMaxChargePower,proto3" json:"instantaneous_max_charge_power,omitempty"`
+	GridServicesPower              float64                `protobuf:"fixed64,14,opt,name=grid_services_power,json=gridServicesPower,proto3" json:"grid_services_power,omitempty"`
+	SystemIslandState              string                 `protobuf:"bytes,15,opt,name=system_island_state,json=systemIslandState,proto3" json:"system_island_state,omitempty"`
+	AvailableBlocks                int64                  `protobuf:"varint,16,opt,name=available_blocks,json=availableBlocks,proto3" json:"available_blocks,omitempty"`
+	BatteryBlocks                  []*BatteryBlock        `protobuf:"bytes,17,rep,name=battery_blocks,json=batteryBlocks,proto3" json:"battery_blocks,omitempty"`
+	FfrPowerAvailabilityHigh       float64                `protobuf:"fixed64,18,opt,name=ffr_power_availability_high,json=ffrPowerAvailabilityHigh,proto3" json:"ffr_power_availability_high,omitempty"`
+	FfrPowerAvailabilityLow        float64                `protobuf:"fixed64,19,opt,name=ffr_power_availability_low,json=ffrPowerAvailabilityLow,proto3" json:"ffr_power_availability_low,omitempty"`
+	LoadChargeConstraint           int64                  `protobuf:"varint,20,opt,name=load_charge_constraint,json=loadChargeConstraint,proto3" json:"load_charge_constraint,omitempty"`
+	MaxSustainedRampRate           int64                  `protobuf:"varint,21,opt,name=max_sustained_ramp_rate,json=maxSustainedRampRate,proto3" json:"max_sustained_ramp_rate,omitempty"`
+	GridFaults                     []*GridFault           `protobuf:"bytes,22,rep,name=grid_faults,json=gridFaults,proto3" json:"grid_faults,omitempty"`
+	CanReboot                      string                 `protobuf:"bytes,23,opt,name=can_reboot,json=canReboot,proto3" json:"can_reboot,omitempty"`
+	SmartInvDeltaP                 int64                  `protobuf:"varint,24,opt,name=smart_inv_delta_p,json=smartInvDeltaP,proto3" json:"smart_inv_delta_p,omitempty"`
+	SmartInvDeltaQ                 int64                  `protobuf:"varint,25,opt,name=smart_inv_delta_q,json=smartInvDeltaQ,proto3" json:"smart_inv_delta_q,omitempty"`
+	Updating                       bool                   `protobuf:"varint,26,opt,name=updating,proto3" json:"updating,omitempty"`
+	LastToggleTimestamp            *timestamppb.Timestamp `protobuf:"bytes,27,opt,name=last_toggle_timestamp,json=lastToggleTimestamp,proto3" json:"last_toggle_timestamp,omitempty"`
+	SolarRealPowerLimit            float64                `protobuf:"fixed64,28,opt,name=solar_real_power_limit,json=solarRealPowerLimit,proto3" json:"solar_real_power_limit,omitempty"`
+	Score                          int64                  `protobuf:"varint,29,opt,name=score,proto3" json:"score,omitempty"`
+	BlocksControlled               int64                  `protobuf:"varint,30,opt,name=blocks_controlled,json=blocksControlled,proto3" json:"blocks_controlled,omitempty"`
+	Primary                        bool                   `protobuf:"varint,31,opt,name=primary,proto3" json:"primary,omitempty"`
+	AuxiliaryLoad                  int64                  `protobuf:"varint,32,opt,name=auxiliary_load,json=auxiliaryLoad,proto3" json:"auxiliary_load,omitempty"`
+	AllEnableLinesHigh             bool                   `protobuf:"varint,33,opt,name=all_enable_lines_high,json=allEnableLinesHigh,proto3" json:"all_enable_lines_high,omitempty"`
+	InverterNominalUsablePower     int64                  `protobuf:"varint,34,opt,name=inverter_nominal_usable_power,json=inverterNominalUsablePower,proto3" json:"inverter_nominal_usable_power,omitempty"`
+	ExpectedEnergyRemaining        int64                  `protobuf:"varint,35,opt,name=expected_energy_remaining,json=expectedEnergyRemaining,proto3" json:"expected_energy_remaining,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *SystemStatus) Reset() {
+	*x = SystemStatus{}
+	mi := &file_snapshot_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatus) ProtoMessage() {}
+
+func (x *SystemStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatus.ProtoReflect.Descriptor instead.
+func (*SystemStatus) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SystemStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SystemStatus) GetCommandSource() string {
+	if x != nil {
+		return x.CommandSource
+	}
+	return ""
+}
+
+func (x *SystemStatus) GetBatteryTargetPower() float64 {
+	if x != nil {
+		return x.BatteryTargetPower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetBatteryTargetReactivePower() int64 {
+	if x != nil {
+		return x.BatteryTargetReactivePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetNominalFullPackEnergy() int64 {
+	if x != nil {
+		return x.NominalFullPackEnergy
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetNominalEnergyRemaining() int64 {
+	if x != nil {
+		return x.NominalEnergyRemaining
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxPowerEnergyRemaining() int64 {
+	if x != nil {
+		return x.MaxPowerEnergyRemaining
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxPowerEnergyToBeCharged() int64 {
+	if x != nil {
+		return x.MaxPowerEnergyToBeCharged
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxChargePower() int64 {
+	if x != nil {
+		return x.MaxChargePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxDischargePower() float64 {
+	if x != nil {
+		return x.MaxDischargePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxApparentPower() int64 {
+	if x != nil {
+		return x.MaxApparentPower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetInstantaneousMaxDischargePower() int64 {
+	if x != nil {
+		return x.InstantaneousMaxDischargePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetInstantaneousMaxChargePower() int64 {
+	if x != nil {
+		return x.InstantaneousMaxChargePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetGridServicesPower() float64 {
+	if x != nil {
+		return x.GridServicesPower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetSystemIslandState() string {
+	if x != nil {
+		return x.SystemIslandState
+	}
+	return ""
+}
+
+func (x *SystemStatus) GetAvailableBlocks() int64 {
+	if x != nil {
+		return x.AvailableBlocks
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetBatteryBlocks() []*BatteryBlock {
+	if x != nil {
+		return x.BatteryBlocks
+	}
+	return nil
+}
+
+func (x *SystemStatus) GetFfrPowerAvailabilityHigh() float64 {
+	if x != nil {
+		return x.FfrPowerAvailabilityHigh
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetFfrPowerAvailabilityLow() float64 {
+	if x != nil {
+		return x.FfrPowerAvailabilityLow
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetLoadChargeConstraint() int64 {
+	if x != nil {
+		return x.LoadChargeConstraint
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetMaxSustainedRampRate() int64 {
+	if x != nil {
+		return x.MaxSustainedRampRate
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetGridFaults() []*GridFault {
+	if x != nil {
+		return x.GridFaults
+	}
+	return nil
+}
+
+func (x *SystemStatus) GetCanReboot() string {
+	if x != nil {
+		return x.CanReboot
+	}
+	return ""
+}
+
+func (x *SystemStatus) GetSmartInvDeltaP() int64 {
+	if x != nil {
+		return x.SmartInvDeltaP
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetSmartInvDeltaQ() int64 {
+	if x != nil {
+		return x.SmartInvDeltaQ
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetUpdating() bool {
+	if x != nil {
+		return x.Updating
+	}
+	return false
+}
+
+func (x *SystemStatus) GetLastToggleTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastToggleTimestamp
+	}
+	return nil
+}
+
+func (x *SystemStatus) GetSolarRealPowerLimit() float64 {
+	if x != nil {
+		return x.SolarRealPowerLimit
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetBlocksControlled() int64 {
+	if x != nil {
+		return x.BlocksControlled
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+func (x *SystemStatus) GetAuxiliaryLoad() int64 {
+	if x != nil {
+		return x.AuxiliaryLoad
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetAllEnableLinesHigh() bool {
+	if x != nil {
+		return x.AllEnableLinesHigh
+	}
+	return false
+}
+
+func (x *SystemStatus) GetInverterNominalUsablePower() int64 {
+	if x != nil {
+		return x.InverterNominalUsablePower
+	}
+	return 0
+}
+
+func (x *SystemStatus) GetExpectedEnergyRemaining() int64 {
+	if x != nil {
+		return x.ExpectedEnergyRemaining
+	}
+	return 0
+}
+
+// BatteryBlock is one battery block of /api/system_status
+type BatteryBlock struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Type                   string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	PackagePartNumber      string                 `protobuf:"bytes,2,opt,name=package_part_number,json=packagePartNumber,proto3" json:"package_part_number,omitempty"`
+	PackageSerialNumber    string                 `protobuf:"bytes,3,opt,name=package_serial_number,json=packageSerialNumber,proto3" json:"package_serial_number,omitempty"`
+	DisabledReasons        []string               `protobuf:"bytes,4,rep,name=disabled_reasons,json=disabledReasons,proto3" json:"disabled_reasons,omitempty"`
+	PinvState              string                 `protobuf:"bytes,5,opt,name=pinv_state,json=pinvState,proto3" json:"pinv_state,omitempty"`
+	PinvGridState          string                 `protobuf:"bytes,6,opt,name=pinv_grid_state,json=pinvGridState,proto3" json:"pinv_grid_state,omitempty"`
+	NominalEnergyRemaining int64                  `protobuf:"varint,7,opt,name=nominal_energy_remaining,json=nominalEnergyRemaining,proto3" json:"nominal_energy_remaining,omitempty"`
+	NominalFullPackEnergy  int64                  `protobuf:"varint,8,opt,name=nominal_full_pack_energy,json=nominalFullPackEnergy,proto3" json:"nominal_full_pack_energy,omitempty"`
+	POut                   float64                `protobuf:"fixed64,9,opt,name=p_out,json=pOut,proto3" json:"p_out,omitempty"`
+	QOut                   float64                `protobuf:"fixed64,10,opt,name=q_out,json=qOut,proto3" json:"q_out,omitempty"`
+	VOut                   float64                `protobuf:"fixed64,11,opt,name=v_out,json=vOut,proto3" json:"v_out,omitempty"`
+	FOut                   float64                `protobuf:"fixed64,12,opt,name=f_out,json=fOut,proto3" json:"f_out,omitempty"`
+	IOut                   float64                `protobuf:"fixed64,13,opt,name=i_out,json=iOut,proto3" json:"i_out,omitempty"`
+	EnergyCharged          int64                  `protobuf:"varint,14,opt,name=energy_charged,json=energyCharged,proto3" json:"energy_charged,omitempty"`
+	EnergyDischarged       int64                  `protobuf:"varint,15,opt,name=energy_discharged,json=energyDischarged,proto3" json:"energy_discharged,omitempty"`
+	OffGrid                bool                   `protobuf:"varint,16,opt,name=off_grid,json=offGrid,proto3" json:"off_grid,omitempty"`
+	VfMode                 bool                   `protobuf:"varint,17,opt,name=vf_mode,json=vfMode,proto3" json:"vf_mode,omitempty"`
+	WobbleDetected         bool                   `protobuf:"varint,18,opt,name=wobble_detected,json=wobbleDetected,proto3" json:"wobble_detected,omitempty"`
+	ChargePowerClamped     bool                   `protobuf:"varint,19,opt,name=charge_power_clamped,json=chargePowerClamped,proto3" json:"charge_power_clamped,omitempty"`
+	BackupReady            bool                   `protobuf:"varint,20,opt,name=backup_ready,json=backupReady,proto3" json:"backup_ready,omitempty"`
+	OpSeqState             string                 `protobuf:"bytes,21,opt,name=op_seq_state,json=opSeqState,proto3" json:"op_seq_state,omitempty"`
+	Version                string                 `protobuf:"bytes,22,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *BatteryBlock) Reset() {
+	*x = BatteryBlock{}
+	mi := &file_snapshot_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatteryBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatteryBlock) ProtoMessage() {}
+
+func (x *BatteryBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatteryBlock.ProtoReflect.Descriptor instead.
+func (*BatteryBlock) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BatteryBlock) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetPackagePartNumber() string {
+	if x != nil {
+		return x.PackagePartNumber
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetPackageSerialNumber() string {
+	if x != nil {
+		return x.PackageSerialNumber
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetDisabledReasons() []string {
+	if x != nil {
+		return x.DisabledReasons
+	}
+	return nil
+}
+
+func (x *BatteryBlock) GetPinvState() string {
+	if x != nil {
+		return x.PinvState
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetPinvGridState() string {
+	if x != nil {
+		return x.PinvGridState
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetNominalEnergyRemaining() int64 {
+	if x != nil {
+		return x.NominalEnergyRemaining
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetNominalFullPackEnergy() int64 {
+	if x != nil {
+		return x.NominalFullPackEnergy
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetPOut() float64 {
+	if x != nil {
+		return x.POut
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetQOut() float64 {
+	if x != nil {
+		return x.QOut
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetVOut() float64 {
+	if x != nil {
+		return x.VOut
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetFOut() float64 {
+	if x != nil {
+		return x.FOut
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetIOut() float64 {
+	if x != nil {
+		return x.IOut
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetEnergyCharged() int64 {
+	if x != nil {
+		return x.EnergyCharged
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetEnergyDischarged() int64 {
+	if x != nil {
+		return x.EnergyDischarged
+	}
+	return 0
+}
+
+func (x *BatteryBlock) GetOffGrid() bool {
+	if x != nil {
+		return x.OffGrid
+	}
+	return false
+}
+
+func (x *BatteryBlock) GetVfMode() bool {
+	if x != nil {
+		return x.VfMode
+	}
+	return false
+}
+
+func (x *BatteryBlock) GetWobbleDetected() bool {
+	if x != nil {
+		return x.WobbleDetected
+	}
+	return false
+}
+
+func (x *BatteryBlock) GetChargePowerClamped() bool {
+	if x != nil {
+		return x.ChargePowerClamped
+	}
+	return false
+}
+
+func (x *BatteryBlock) GetBackupReady() bool {
+	if x != nil {
+		return x.BackupReady
+	}
+	return false
+}
+
+func (x *BatteryBlock) GetOpSeqState() string {
+	if x != nil {
+		return x.OpSeqState
+	}
+	return ""
+}
+
+func (x *BatteryBlock) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// GridFault is one grid fault of /api/system_status
+type GridFault struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timestamp is in Unix milliseconds
+	Timestamp              int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	AlertName              string       `protobuf:"bytes,2,opt,name=alert_name,json=alertName,proto3" json:"alert_name,omitempty"`
+	AlertIsFault           bool         `protobuf:"varint,3,opt,name=alert_is_fault,json=alertIsFault,proto3" json:"alert_is_fault,omitempty"`
+	DecodedAlert           []*GridAlert `protobuf:"bytes,4,rep,name=decoded_alert,json=decodedAlert,proto3" json:"decoded_alert,omitempty"`
+	AlertRaw               int64        `protobuf:"varint,5,opt,name=alert_raw,json=alertRaw,proto3" json:"alert_raw,omitempty"`
+	GitHash                string       `protobuf:"bytes,6,opt,name=git_hash,json=gitHash,proto3" json:"git_hash,omitempty"`
+	SiteUid                string       `protobuf:"bytes,7,opt,name=site_uid,json=siteUid,proto3" json:"site_uid,omitempty"`
+	EcuType                string       `protobuf:"bytes,8,opt,name=ecu_type,json=ecuType,proto3" json:"ecu_type,omitempty"`
+	EcuPackagePartNumber   string       `protobuf:"bytes,9,opt,name=ecu_package_part_number,json=ecuPackagePartNumber,proto3" json:"ecu_package_part_number,omitempty"`
+	EcuPackageSerialNumber string       `protobuf:"bytes,10,opt,name=ecu_package_serial_number,json=ecuPackageSerialNumber,proto3" json:"ecu_package_serial_number,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GridFault) Reset() {
+	*x = GridFault{}
+	mi := &file_snapshot_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GridFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GridFault) ProtoMessage() {}
+
+func (x *GridFault) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GridFault.ProtoReflect.Descriptor instead.
+func (*GridFault) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GridFault) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GridFault) GetAlertName() string {
+	if x != nil {
+		return x.AlertName
+	}
+	return ""
+}
+
+func (x *GridFault) GetAlertIsFault() bool {
+	if x != nil {
+		return x.AlertIsFault
+	}
+	return false
+}
+
+func (x *GridFault) GetDecodedAlert() []*GridAlert {
+	if x != nil {
+		return x.DecodedAlert
+	}
+	return nil
+}
+
+func (x *GridFault) GetAlertRaw() int64 {
+	if x != nil {
+		return x.AlertRaw
+	}
+	return 0
+}
+
+func (x *GridFault) GetGitHash() string {
+	if x != nil {
+		return x.GitHash
+	}
+	return ""
+}
+
+func (x *GridFault) GetSiteUid() string {
+	if x != nil {
+		return x.SiteUid
+	}
+	return ""
+}
+
+func (x *GridFault) GetEcuType() string {
+	if x != nil {
+		return x.EcuType
+	}
+	return ""
+}
+
+func (x *GridFault) GetEcuPackagePartNumber() string {
+	if x != nil {
+		return x.EcuPackagePartNumber
+	}
+	return ""
+}
+
+func (x *GridFault) GetEcuPackageSerialNumber() string {
+	if x != nil {
+		return x.EcuPackageSerialNumber
+	}
+	return ""
+}
+
+// GridAlert is one decoded value of a grid fault
+type GridAlert struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// value is a string or a number
+	Value         *structpb.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Units         string          `protobuf:"bytes,3,opt,name=units,proto3" json:"units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GridAlert) Reset() {
+	*x = GridAlert{}
+	mi := &file_snapshot_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GridAlert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GridAlert) ProtoMessage() {}
+
+func (x *GridAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GridAlert.ProtoReflect.Descriptor instead.
+func (*GridAlert) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GridAlert) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GridAlert) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *GridAlert) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+// SystemGridStatus is /api/system_status/grid_status
+type SystemGridStatus struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	GridStatus         string                 `protobuf:"bytes,2,opt,name=grid_status,json=gridStatus,proto3" json:"grid_status,omitempty"`
+	GridServicesActive bool                   `protobuf:"varint,3,opt,name=grid_services_active,json=gridServicesActive,proto3" json:"grid_services_active,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SystemGridStatus) Reset() {
+	*x = SystemGridStatus{}
+	mi := &file_snapshot_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemGridStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemGridStatus) ProtoMessage() {}
+
+func (x *SystemGridStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemGridStatus.ProtoReflect.Descriptor instead.
+func (*SystemGridStatus) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SystemGridStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SystemGridStatus) GetGridStatus() string {
+	if x != nil {
+		return x.GridStatus
+	}
+	return ""
+}
+
+func (x *SystemGridStatus) GetGridServicesActive() bool {
+	if x != nil {
+		return x.GridServicesActive
+	}
+	return false
+}
+
+// SystemStateOfEnergy is /api/system_status/soe
+type SystemStateOfEnergy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Percentage    float64                `protobuf:"fixed64,2,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemStateOfEnergy) Reset() {
+	*x = SystemStateOfEnergy{}
+	mi := &file_snapshot_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStateOfEnergy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStateOfEnergy) ProtoMessage() {}
+
+func (x *SystemStateOfEnergy) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStateOfEnergy.ProtoReflect.Descriptor instead.
+func (*SystemStateOfEnergy) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SystemStateOfEnergy) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SystemStateOfEnergy) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+var File_snapshot_proto protoreflect.FileDescriptor
+
+var file_snapshot_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x26, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbc, 0x01, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x49, 0x64, 0x12, 0x38, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x3d, 0x0a, 0x03, 0x74, 0x65, 0x67, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65,
+	0x67, 0x52, 0x03, 0x74, 0x65, 0x67, 0x22, 0xe7, 0x09, 0x0a, 0x03, 0x54, 0x65, 0x67, 0x12, 0x46,
+	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x52, 0x06,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x59, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e,
+	0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x0c, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x4f, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x52, 0x0a, 0x0a, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73, 0x52, 0x0a, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x4d, 0x0a, 0x09, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69,
+	0x6e, 0x66, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x74, 0x65, 0x73, 0x6c,
+	0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x73, 0x69, 0x74,
+	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x52, 0x0a, 0x0a, 0x73, 0x69, 0x74, 0x65, 0x6d, 0x61, 0x73,
+	0x74, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x74, 0x65, 0x73, 0x6c,
+	0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x52, 0x0a, 0x73,
+	0x69, 0x74, 0x65, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x12, 0x45, 0x0a, 0x06, 0x73, 0x6f, 0x6c,
+	0x61, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x74, 0x65, 0x73, 0x6c,
+	0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x6f, 0x6c, 0x61, 0x72, 0x52, 0x06, 0x73, 0x6f, 0x6c, 0x61, 0x72, 0x73,
+	0x12, 0x46, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x78, 0x0a, 0x18, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x74, 0x65, 0x73,
+	0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x52, 0x16, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x73,
+	0x74, 0x73, 0x12, 0x5c, 0x0a, 0x0e, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x74, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x74, 0x65, 0x73,
+	0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x67, 0x52, 0x0d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x67,
+	0x12, 0x59, 0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f,
+	0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x59, 0x0a, 0x0d, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x34, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x66, 0x0a, 0x12, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x5f, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x38, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x47, 0x72, 0x69, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x10, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x47, 0x72, 0x69, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x70,
+	0x0a, 0x16, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6f,
+	0x66, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b,
+	0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x4f, 0x66, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x52, 0x13, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4f, 0x66, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79,
+	0x22, 0xfe, 0x02, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x4b, 0x0a, 0x04, 0x73, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x04, 0x73, 0x69,
+	0x74, 0x65, 0x12, 0x51, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x07, 0x62, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x79, 0x12, 0x4b, 0x0a, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x04, 0x6c, 0x6f,
+	0x61, 0x64, 0x12, 0x4d, 0x0a, 0x05, 0x73, 0x6f, 0x6c, 0x61, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x37, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x65, 0x72,
+	0x73, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x6f, 0x6c, 0x61,
+	0x72, 0x22, 0xd8, 0x06, 0x0a, 0x0f, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x52, 0x0a, 0x17, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x15, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x34,
+	0x0a, 0x16, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x16, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x5f,
+	0x61, 0x70, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x41, 0x70, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0e, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x69, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x36, 0x0a, 0x17, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x74, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x76, 0x6f,
+	0x6c, 0x74, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x74, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x56, 0x6f, 0x6c, 0x74, 0x61,
+	0x67, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x5f, 0x61, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x15, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x41, 0x76, 0x65, 0x72,
+	0x61, 0x67, 0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x69, 0x5f,
+	0x61, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x69, 0x41, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x69, 0x5f,
+	0x62, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x69, 0x42, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x69, 0x5f,
+	0x63, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x69, 0x43, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x50, 0x0a, 0x25, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65,
+	0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x21, 0x6c, 0x61, 0x73, 0x74, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x75,
+	0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x4c, 0x0a, 0x23,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1f, 0x6c, 0x61, 0x73, 0x74, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x6e, 0x75, 0x6d, 0x5f, 0x6d, 0x65, 0x74, 0x65,
+	0x72, 0x73, 0x5f, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x13, 0x6e, 0x75, 0x6d, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0xa8, 0x01, 0x0a,
+	0x0c, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x2e, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0x8f, 0x02, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x6c, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x61, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x34, 0x0a, 0x16,
+	0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14, 0x62, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x12, 0x36, 0x0a, 0x18, 0x66, 0x72, 0x65, 0x71, 0x5f, 0x73, 0x68, 0x69, 0x66, 0x74,
+	0x5f, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x73, 0x6f, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x66, 0x72, 0x65, 0x71, 0x53, 0x68, 0x69, 0x66, 0x74, 0x4c,
+	0x6f, 0x61, 0x64, 0x53, 0x68, 0x65, 0x64, 0x53, 0x6f, 0x65, 0x12, 0x3d, 0x0a, 0x1c, 0x66, 0x72,
+	0x65, 0x71, 0x5f, 0x73, 0x68, 0x69, 0x66, 0x74, 0x5f, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x68,
+	0x65, 0x64, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x5f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x17, 0x66, 0x72, 0x65, 0x71, 0x53, 0x68, 0x69, 0x66, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x53,
+	0x68, 0x65, 0x64, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x46, 0x22, 0xb9, 0x06, 0x0a, 0x0a, 0x50, 0x6f,
+	0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x4a, 0x0a, 0x04, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x36, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x77,
+	0x61, 0x6c, 0x6c, 0x73, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x04, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x51,
+	0x0a, 0x0a, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x31, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65,
+	0x72, 0x77, 0x61, 0x6c, 0x6c, 0x52, 0x0a, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c,
+	0x73, 0x12, 0x28, 0x0a, 0x03, 0x6d, 0x73, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x5f, 0x64, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x44, 0x69, 0x6e, 0x12, 0x3b, 0x0a, 0x1a,
+	0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x17, 0x70, 0x68, 0x61, 0x73, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2d, 0x0a, 0x13, 0x6f, 0x6e, 0x5f,
+	0x67, 0x72, 0x69, 0x64, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f, 0x6e, 0x47, 0x72, 0x69, 0x64, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x75, 0x62, 0x62,
+	0x6c, 0x65, 0x5f, 0x73, 0x68, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x62, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x65, 0x64, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x30, 0x0a, 0x14, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x12, 0x67, 0x72, 0x69, 0x64, 0x43, 0x6f, 0x64, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x12, 0x41, 0x0a, 0x1d, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x64, 0x65, 0x74,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x76, 0x61, 0x69, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x70, 0x68, 0x61, 0x73,
+	0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x41, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e,
+	0x67, 0x5f, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67,
+	0x50, 0x68, 0x61, 0x73, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e,
+	0x0a, 0x13, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x66, 0x5f, 0x6f, 0x66,
+	0x66, 0x67, 0x72, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x69, 0x6e, 0x67, 0x49, 0x66, 0x4f, 0x66, 0x66, 0x67, 0x72, 0x69, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e,
+	0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x65, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x27, 0x0a, 0x0f,
+	0x67, 0x72, 0x69, 0x64, 0x5f, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x67, 0x72, 0x69, 0x64, 0x51, 0x75, 0x61, 0x6c, 0x69,
+	0x66, 0x79, 0x69, 0x6e, 0x67, 0x22, 0x81, 0x05, 0x0a, 0x09, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x77,
+	0x61, 0x6c, 0x6c, 0x12, 0x76, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e,
+	0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x52, 0x17, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x69, 0x6e,
+	0x67, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x12, 0x68, 0x0a, 0x11, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x52, 0x10, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50,
+	0x61, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x72, 0x69,
+	0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67,
+	0x72, 0x69, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x62, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x75, 0x62, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x62, 0x63, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x06, 0x62, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x43, 0x0a, 0x1e, 0x67, 0x72, 0x69, 0x64, 0x5f,
+	0x72, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x1b, 0x67, 0x72, 0x69, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x32, 0x0a, 0x15,
+	0x75, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x75, 0x6e, 0x64,
+	0x65, 0x72, 0x50, 0x68, 0x61, 0x73, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1b, 0x0a, 0x09,
+	0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x8e, 0x02, 0x0a, 0x0e, 0x50, 0x6f,
+	0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x73, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x1a, 0x0a, 0x08,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x76, 0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f,
+	0x73, 0x74, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x74, 0x65, 0x73,
+	0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x44, 0x69, 0x61,
+	0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x17, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63,
+	0x12, 0x68, 0x0a, 0x11, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x74, 0x65,
+	0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73,
+	0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x44, 0x69,
+	0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x10, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x22, 0xfb, 0x01, 0x0a, 0x13, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x12, 0x4e, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x36, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67,
+	0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65,
+	0x72, 0x77, 0x61, 0x6c, 0x6c, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63,
+	0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x12, 0x2e, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75,
+	0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69, 0x76, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70, 0x74, 0x69,
+	0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x22, 0xf4, 0x02, 0x0a, 0x0e, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x77, 0x61, 0x6c, 0x6c, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x30, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x2c, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12,
+	0x2e, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x22,
+	0x8d, 0x05, 0x0a, 0x08, 0x53, 0x69, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x38, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x12, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72,
+	0x65, 0x64, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x11, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x64, 0x46, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x31, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6b, 0x77, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x4b, 0x77, 0x68, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x6b, 0x77, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x50, 0x6f, 0x77, 0x65, 0x72, 0x4b, 0x77, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x69, 0x74, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x69, 0x74, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x65, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6e, 0x65, 0x74, 0x4d, 0x65, 0x74,
+	0x65, 0x72, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x34, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69,
+	0x74, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x6b,
+	0x77, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x74, 0x65,
+	0x4d, 0x65, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x4b, 0x77, 0x12, 0x34, 0x0a, 0x17,
+	0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x5f, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x5f, 0x6b, 0x77, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6d,
+	0x69, 0x6e, 0x53, 0x69, 0x74, 0x65, 0x4d, 0x65, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x77, 0x65, 0x72,
+	0x4b, 0x77, 0x12, 0x39, 0x0a, 0x19, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6b, 0x77, 0x68, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x16, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x53, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x4b, 0x77, 0x68, 0x12, 0x35, 0x0a,
+	0x17, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f,
+	0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x6b, 0x77, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x14,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x4b, 0x77, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x61, 0x6e, 0x65, 0x6c, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x70, 0x61, 0x6e, 0x65, 0x6c, 0x4d, 0x61, 0x78, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x12, 0x4d, 0x0a, 0x09, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72,
+	0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x69,
+	0x64, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x67, 0x72, 0x69, 0x64, 0x43, 0x6f, 0x64, 0x65, 0x22,
+	0xfd, 0x01, 0x0a, 0x08, 0x47, 0x72, 0x69, 0x64, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x67, 0x72, 0x69, 0x64, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x67, 0x72, 0x69, 0x64, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x67, 0x72, 0x69,
+	0x64, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x67, 0x72, 0x69, 0x64, 0x56, 0x6f, 0x6c,
+	0x74, 0x61, 0x67, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x67,
+	0x72, 0x69, 0x64, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x67, 0x72, 0x69, 0x64, 0x46, 0x72, 0x65, 0x71,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x67, 0x72, 0x69, 0x64, 0x5f,
+	0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x67, 0x72, 0x69, 0x64, 0x50, 0x68, 0x61, 0x73, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22,
+	0xf1, 0x01, 0x0a, 0x0a, 0x53, 0x69, 0x74, 0x65, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x12, 0x38,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x5f, 0x74, 0x65, 0x73, 0x6c, 0x61,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x54, 0x6f, 0x54, 0x65, 0x73, 0x6c, 0x61, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x5f, 0x72, 0x65, 0x62, 0x6f,
+	0x6f, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x62,
+	0x6f, 0x6f, 0x74, 0x22, 0x9b, 0x01, 0x0a, 0x05, 0x53, 0x6f, 0x6c, 0x61, 0x72, 0x12, 0x38, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x5f, 0x77, 0x61, 0x74, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x10, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x57, 0x61, 0x74, 0x74,
+	0x73, 0x22, 0xdd, 0x03, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x69, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f, 0x6e, 0x65, 0x77,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x4e, 0x65, 0x77, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x69, 0x74, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x69, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x79, 0x6e, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x6c,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x09, 0x66,
+	0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x09, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72,
+	0x73, 0x22, 0xc0, 0x02, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x72, 0x75, 0x70,
+	0x74, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x72,
+	0x75, 0x70, 0x74, 0x69, 0x76, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x56, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x22, 0xc6, 0x02, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x30, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12,
+	0x2c, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12, 0x2e, 0x0a,
+	0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x22, 0xca, 0x03,
+	0x0a, 0x0d, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x12,
+	0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x75, 0x6e,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e,
+	0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x0c, 0x63,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x72, 0x67,
+	0x65, 0x54, 0x65, 0x73, 0x74, 0x73, 0x12, 0x3b, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x5f,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x12, 0x41, 0x0a, 0x10, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x72, 0x5f,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0f, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x68, 0x79, 0x73, 0x74, 0x65, 0x72,
+	0x65, 0x73, 0x69, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x68, 0x79, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x73, 0x69, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x06,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x05,
+	0x74, 0x65, 0x73, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x05, 0x74, 0x65, 0x73, 0x74, 0x73, 0x22, 0x98, 0x03, 0x0a, 0x0c, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69,
+	0x6e, 0x66, 0x6f, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x69, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x28, 0x0a, 0x10, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x6f, 0x66, 0x66, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6f,
+	0x66, 0x66, 0x6c, 0x69, 0x6e, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x30,
+	0x0a, 0x14, 0x6f, 0x66, 0x66, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6f, 0x66,
+	0x66, 0x6c, 0x69, 0x6e, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x53, 0x0a, 0x1a, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x17, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x22, 0xe0, 0x0e, 0x0a, 0x0c, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x62, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x79, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x41, 0x0a, 0x1d, 0x62, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1a, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52,
+	0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x18,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x66, 0x75, 0x6c, 0x6c, 0x5f, 0x70, 0x61, 0x63,
+	0x6b, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x63, 0x6b, 0x45,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x12, 0x38, 0x0a, 0x18, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12,
+	0x3b, 0x0a, 0x1a, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x65, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x17, 0x6d, 0x61, 0x78, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x45, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x41, 0x0a, 0x1e,
+	0x6d, 0x61, 0x78, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79,
+	0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x19, 0x6d, 0x61, 0x78, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x45, 0x6e,
+	0x65, 0x72, 0x67, 0x79, 0x54, 0x6f, 0x42, 0x65, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x64, 0x12,
+	0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70, 0x6f,
+	0x77, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x43, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x61, 0x78,
+	0x5f, 0x64, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x63, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x78,
+	0x5f, 0x61, 0x70, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x41, 0x70, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x49, 0x0a, 0x21, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x74, 0x61, 0x6e, 0x65, 0x6f, 0x75, 0x73, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x73,
+	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x1e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x61, 0x6e, 0x65, 0x6f, 0x75,
+	0x73, 0x4d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x12, 0x43, 0x0a, 0x1e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x61, 0x6e, 0x65,
+	0x6f, 0x75, 0x73, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1b, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x74, 0x61, 0x6e, 0x65, 0x6f, 0x75, 0x73, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x67, 0x72, 0x69, 0x64, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x67, 0x72, 0x69, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x5f, 0x69, 0x73, 0x6c, 0x61, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x73, 0x6c, 0x61,
+	0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x76, 0x61, 0x69, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0f, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x5b, 0x0a, 0x0e, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x74, 0x65, 0x73,
+	0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x0d, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12,
+	0x3d, 0x0a, 0x1b, 0x66, 0x66, 0x72, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x61, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x18, 0x66, 0x66, 0x72, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x41, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x48, 0x69, 0x67, 0x68, 0x12, 0x3b,
+	0x0a, 0x1a, 0x66, 0x66, 0x72, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x6f, 0x77, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x17, 0x66, 0x66, 0x72, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x41, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4c, 0x6f, 0x77, 0x12, 0x34, 0x0a, 0x16, 0x6c,
+	0x6f, 0x61, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74,
+	0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x6c, 0x6f, 0x61,
+	0x64, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x12, 0x35, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x75, 0x73, 0x74, 0x61, 0x69, 0x6e,
+	0x65, 0x64, 0x5f, 0x72, 0x61, 0x6d, 0x70, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x14, 0x6d, 0x61, 0x78, 0x53, 0x75, 0x73, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x64,
+	0x52, 0x61, 0x6d, 0x70, 0x52, 0x61, 0x74, 0x65, 0x12, 0x52, 0x0a, 0x0b, 0x67, 0x72, 0x69, 0x64,
+	0x5f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e,
+	0x74, 0x65, 0x73, 0x6c, 0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x46, 0x61, 0x75, 0x6c, 0x74,
+	0x52, 0x0a, 0x67, 0x72, 0x69, 0x64, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x61, 0x6e, 0x5f, 0x72, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x12, 0x29, 0x0a, 0x11, 0x73,
+	0x6d, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x5f, 0x70,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x6d, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x76,
+	0x44, 0x65, 0x6c, 0x74, 0x61, 0x50, 0x12, 0x29, 0x0a, 0x11, 0x73, 0x6d, 0x61, 0x72, 0x74, 0x5f,
+	0x69, 0x6e, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x5f, 0x71, 0x18, 0x19, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x73, 0x6d, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61,
+	0x51, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x1a, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x75, 0x70, 0x64, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x4e, 0x0a,
+	0x15, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x13, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x6f,
+	0x67, 0x67, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x33, 0x0a,
+	0x16, 0x73, 0x6f, 0x6c, 0x61, 0x72, 0x5f, 0x72, 0x65, 0x61, 0x6c, 0x5f, 0x70, 0x6f, 0x77, 0x65,
+	0x72, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x73,
+	0x6f, 0x6c, 0x61, 0x72, 0x52, 0x65, 0x61, 0x6c, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x1d, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x1e, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x10, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79,
+	0x18, 0x1f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x12,
+	0x25, 0x0a, 0x0e, 0x61, 0x75, 0x78, 0x69, 0x6c, 0x69, 0x61, 0x72, 0x79, 0x5f, 0x6c, 0x6f, 0x61,
+	0x64, 0x18, 0x20, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x61, 0x75, 0x78, 0x69, 0x6c, 0x69, 0x61,
+	0x72, 0x79, 0x4c, 0x6f, 0x61, 0x64, 0x12, 0x31, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x5f, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x18,
+	0x21, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x61, 0x6c, 0x6c, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x4c, 0x69, 0x6e, 0x65, 0x73, 0x48, 0x69, 0x67, 0x68, 0x12, 0x41, 0x0a, 0x1d, 0x69, 0x6e, 0x76,
+	0x65, 0x72, 0x74, 0x65, 0x72, 0x5f, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x22, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1a, 0x69, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x72, 0x4e, 0x6f, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x55, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x3a, 0x0a, 0x19,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x23, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x17, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x52,
+	0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x96, 0x06, 0x0a, 0x0c, 0x42, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a,
+	0x13, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x50, 0x61, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x32, 0x0a,
+	0x15, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x5f, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x69, 0x6e, 0x76, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x69, 0x6e, 0x76, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x70,
+	0x69, 0x6e, 0x76, 0x5f, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x69, 0x6e, 0x76, 0x47, 0x72, 0x69, 0x64, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x38, 0x0a, 0x18, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x65,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x45, 0x6e,
+	0x65, 0x72, 0x67, 0x79, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x37, 0x0a,
+	0x18, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x66, 0x75, 0x6c, 0x6c, 0x5f, 0x70, 0x61,
+	0x63, 0x6b, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x15, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x63, 0x6b,
+	0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x12, 0x13, 0x0a, 0x05, 0x70, 0x5f, 0x6f, 0x75, 0x74, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x70, 0x4f, 0x75, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x71,
+	0x5f, 0x6f, 0x75, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x71, 0x4f, 0x75, 0x74,
+	0x12, 0x13, 0x0a, 0x05, 0x76, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x04, 0x76, 0x4f, 0x75, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x66, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x66, 0x4f, 0x75, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x69, 0x5f,
+	0x6f, 0x75, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x69, 0x4f, 0x75, 0x74, 0x12,
+	0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65,
+	0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x43,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79,
+	0x5f, 0x64, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x10, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x44, 0x69, 0x73, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x66, 0x66, 0x5f, 0x67, 0x72, 0x69, 0x64, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6f, 0x66, 0x66, 0x47, 0x72, 0x69, 0x64, 0x12, 0x17,
+	0x0a, 0x07, 0x76, 0x66, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x76, 0x66, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x77, 0x6f, 0x62, 0x62, 0x6c,
+	0x65, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x77, 0x6f, 0x62, 0x62, 0x6c, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x12, 0x30, 0x0a, 0x14, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x5f, 0x63, 0x6c, 0x61, 0x6d, 0x70, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12,
+	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6c, 0x61, 0x6d, 0x70,
+	0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x72, 0x65, 0x61,
+	0x64, 0x79, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x20, 0x0a, 0x0c, 0x6f, 0x70, 0x5f, 0x73, 0x65, 0x71, 0x5f,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x70, 0x53,
+	0x65, 0x71, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0xa6, 0x03, 0x0a, 0x09, 0x47, 0x72, 0x69, 0x64, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a,
+	0x0a, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0e,
+	0x61, 0x6c, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x73, 0x5f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x49, 0x73, 0x46, 0x61, 0x75,
+	0x6c, 0x74, 0x12, 0x56, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x6c,
+	0x65, 0x72, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x74, 0x65, 0x73, 0x6c,
+	0x61, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x72, 0x69, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x0c, 0x64, 0x65,
+	0x63, 0x6f, 0x64, 0x65, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c,
+	0x65, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61,
+	0x6c, 0x65, 0x72, 0x74, 0x52, 0x61, 0x77, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x69, 0x74, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x69, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x75, 0x69, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x69, 0x74, 0x65, 0x55, 0x69, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x65, 0x63, 0x75, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x65, 0x63, 0x75, 0x54, 0x79, 0x70, 0x65, 0x12, 0x35, 0x0a, 0x17, 0x65, 0x63, 0x75, 0x5f,
+	0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x65, 0x63, 0x75, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x61, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x39, 0x0a, 0x19, 0x65, 0x63, 0x75, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x16, 0x65, 0x63, 0x75, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x53, 0x65,
+	0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x63, 0x0a, 0x09, 0x47, 0x72,
+	0x69, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x22,
+	0x9f, 0x01, 0x0a, 0x10, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x47, 0x72, 0x69, 0x64, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f,
+	0x0a, 0x0b, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x72, 0x69, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x30, 0x0a, 0x14, 0x67, 0x72, 0x69, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x67,
+	0x72, 0x69, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x41, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x22, 0x6f, 0x0a, 0x13, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x4f, 0x66, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61,
+	0x67, 0x65, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x69, 0x77, 0x76, 0x65, 0x6c, 0x61, 0x6e, 0x64, 0x6f, 0x2f, 0x74, 0x65, 0x73, 0x6c, 0x61,
+	0x2d, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x2d, 0x73, 0x74, 0x61, 0x74, 0x73, 0x2d, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2f, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_snapshot_proto_rawDescOnce sync.Once
+	file_snapshot_proto_rawDescData = file_snapshot_proto_rawDesc
+)
+
+func file_snapshot_proto_rawDescGZIP() []byte {
+	file_snapshot_proto_rawDescOnce.Do(func() {
+		file_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(file_snapshot_proto_rawDescData)
+	})
+	return file_snapshot_proto_rawDescData
+}
+
+var file_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_snapshot_proto_goTypes = []any{
+	(*Snapshot)(nil),               // 0: tesla_energy_stats_collector.stream.v1.Snapshot
+	(*Teg)(nil),                    // 1: tesla_energy_stats_collector.stream.v1.Teg
+	(*Meters)(nil),                 // 2: tesla_energy_stats_collector.stream.v1.Meters
+	(*MetersAggregate)(nil),        // 3: tesla_energy_stats_collector.stream.v1.MetersAggregate
+	(*MetersStatus)(nil),           // 4: tesla_energy_stats_collector.stream.v1.MetersStatus
+	(*Operation)(nil),              // 5: tesla_energy_stats_collector.stream.v1.Operation
+	(*Powerwalls)(nil),             // 6: tesla_energy_stats_collector.stream.v1.Powerwalls
+	(*Powerwall)(nil),              // 7: tesla_energy_stats_collector.stream.v1.Powerwall
+	(*PowerwallsSync)(nil),         // 8: tesla_energy_stats_collector.stream.v1.PowerwallsSync
+	(*PowerwallDiagnostic)(nil),    // 9: tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic
+	(*PowerwallCheck)(nil),         // 10: tesla_energy_stats_collector.stream.v1.PowerwallCheck
+	(*SiteInfo)(nil),               // 11: tesla_energy_stats_collector.stream.v1.SiteInfo
+	(*GridCode)(nil),               // 12: tesla_energy_stats_collector.stream.v1.GridCode
+	(*Sitemaster)(nil),             // 13: tesla_energy_stats_collector.stream.v1.Sitemaster
+	(*Solar)(nil),                  // 14: tesla_energy_stats_collector.stream.v1.Solar
+	(*Status)(nil),                 // 15: tesla_energy_stats_collector.stream.v1.Status
+	(*NetworkConnectionTests)(nil), // 16: tesla_energy_stats_collector.stream.v1.NetworkConnectionTests
+	(*NetworkConnectionCheck)(nil), // 17: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck
+	(*SystemTesting)(nil),          // 18: tesla_energy_stats_collector.stream.v1.SystemTesting
+	(*UpdateStatus)(nil),           // 19: tesla_energy_stats_collector.stream.v1.UpdateStatus
+	(*SystemStatus)(nil),           // 20: tesla_energy_stats_collector.stream.v1.SystemStatus
+	(*BatteryBlock)(nil),           // 21: tesla_energy_stats_collector.stream.v1.BatteryBlock
+	(*GridFault)(nil),              // 22: tesla_energy_stats_collector.stream.v1.GridFault
+	(*GridAlert)(nil),              // 23: tesla_energy_stats_collector.stream.v1.GridAlert
+	(*SystemGridStatus)(nil),       // 24: tesla_energy_stats_collector.stream.v1.SystemGridStatus
+	(*SystemStateOfEnergy)(nil),    // 25: tesla_energy_stats_collector.stream.v1.SystemStateOfEnergy
+	(*timestamppb.Timestamp)(nil),  // 26: google.protobuf.Timestamp
+	(*structpb.Value)(nil),         // 27: google.protobuf.Value
+	(*durationpb.Duration)(nil),    // 28: google.protobuf.Duration
+}
+var file_snapshot_proto_depIdxs = []int32{
+	26, // 0: tesla_energy_stats_collector.stream.v1.Snapshot.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 1: tesla_energy_stats_collector.stream.v1.Snapshot.teg:type_name -> tesla_energy_stats_collector.stream.v1.Teg
+	2,  // 2: tesla_energy_stats_collector.stream.v1.Teg.meters:type_name -> tesla_energy_stats_collector.stream.v1.Meters
+	4,  // 3: tesla_energy_stats_collector.stream.v1.Teg.meters_status:type_name -> tesla_energy_stats_collector.stream.v1.MetersStatus
+	5,  // 4: tesla_energy_stats_collector.stream.v1.Teg.operation:type_name -> tesla_energy_stats_collector.stream.v1.Operation
+	6,  // 5: tesla_energy_stats_collector.stream.v1.Teg.powerwalls:type_name -> tesla_energy_stats_collector.stream.v1.Powerwalls
+	11, // 6: tesla_energy_stats_collector.stream.v1.Teg.site_info:type_name -> tesla_energy_stats_collector.stream.v1.SiteInfo
+	13, // 7: tesla_energy_stats_collector.stream.v1.Teg.sitemaster:type_name -> tesla_energy_stats_collector.stream.v1.Sitemaster
+	14, // 8: tesla_energy_stats_collector.stream.v1.Teg.solars:type_name -> tesla_energy_stats_collector.stream.v1.Solar
+	15, // 9: tesla_energy_stats_collector.stream.v1.Teg.status:type_name -> tesla_energy_stats_collector.stream.v1.Status
+	16, // 10: tesla_energy_stats_collector.stream.v1.Teg.network_connection_tests:type_name -> tesla_energy_stats_collector.stream.v1.NetworkConnectionTests
+	18, // 11: tesla_energy_stats_collector.stream.v1.Teg.system_testing:type_name -> tesla_energy_stats_collector.stream.v1.SystemTesting
+	19, // 12: tesla_energy_stats_collector.stream.v1.Teg.update_status:type_name -> tesla_energy_stats_collector.stream.v1.UpdateStatus
+	20, // 13: tesla_energy_stats_collector.stream.v1.Teg.system_status:type_name -> tesla_energy_stats_collector.stream.v1.SystemStatus
+	24, // 14: tesla_energy_stats_collector.stream.v1.Teg.system_grid_status:type_name -> tesla_energy_stats_collector.stream.v1.SystemGridStatus
+	25, // 15: tesla_energy_stats_collector.stream.v1.Teg.system_state_of_energy:type_name -> tesla_energy_stats_collector.stream.v1.SystemStateOfEnergy
+	26, // 16: tesla_energy_stats_collector.stream.v1.Meters.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 17: tesla_energy_stats_collector.stream.v1.Meters.site:type_name -> tesla_energy_stats_collector.stream.v1.MetersAggregate
+	3,  // 18: tesla_energy_stats_collector.stream.v1.Meters.battery:type_name -> tesla_energy_stats_collector.stream.v1.MetersAggregate
+	3,  // 19: tesla_energy_stats_collector.stream.v1.Meters.load:type_name -> tesla_energy_stats_collector.stream.v1.MetersAggregate
+	3,  // 20: tesla_energy_stats_collector.stream.v1.Meters.solar:type_name -> tesla_energy_stats_collector.stream.v1.MetersAggregate
+	26, // 21: tesla_energy_stats_collector.stream.v1.MetersAggregate.last_communication_time:type_name -> google.protobuf.Timestamp
+	26, // 22: tesla_energy_stats_collector.stream.v1.MetersStatus.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 23: tesla_energy_stats_collector.stream.v1.MetersStatus.errors:type_name -> google.protobuf.Value
+	26, // 24: tesla_energy_stats_collector.stream.v1.Operation.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 25: tesla_energy_stats_collector.stream.v1.Powerwalls.timestamp:type_name -> google.protobuf.Timestamp
+	8,  // 26: tesla_energy_stats_collector.stream.v1.Powerwalls.sync:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallsSync
+	7,  // 27: tesla_energy_stats_collector.stream.v1.Powerwalls.powerwalls:type_name -> tesla_energy_stats_collector.stream.v1.Powerwall
+	27, // 28: tesla_energy_stats_collector.stream.v1.Powerwalls.msa:type_name -> google.protobuf.Value
+	27, // 29: tesla_energy_stats_collector.stream.v1.Powerwalls.states:type_name -> google.protobuf.Value
+	9,  // 30: tesla_energy_stats_collector.stream.v1.Powerwall.commissioning_diagnostic:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic
+	9,  // 31: tesla_energy_stats_collector.stream.v1.Powerwall.update_diagnostic:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic
+	27, // 32: tesla_energy_stats_collector.stream.v1.Powerwall.bc_type:type_name -> google.protobuf.Value
+	9,  // 33: tesla_energy_stats_collector.stream.v1.PowerwallsSync.commissioning_diagnostic:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic
+	9,  // 34: tesla_energy_stats_collector.stream.v1.PowerwallsSync.update_diagnostic:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic
+	10, // 35: tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic.checks:type_name -> tesla_energy_stats_collector.stream.v1.PowerwallCheck
+	27, // 36: tesla_energy_stats_collector.stream.v1.PowerwallDiagnostic.inputs:type_name -> google.protobuf.Value
+	26, // 37: tesla_energy_stats_collector.stream.v1.PowerwallCheck.start_time:type_name -> google.protobuf.Timestamp
+	26, // 38: tesla_energy_stats_collector.stream.v1.PowerwallCheck.end_time:type_name -> google.protobuf.Timestamp
+	27, // 39: tesla_energy_stats_collector.stream.v1.PowerwallCheck.results:type_name -> google.protobuf.Value
+	27, // 40: tesla_energy_stats_collector.stream.v1.PowerwallCheck.debug:type_name -> google.protobuf.Value
+	27, // 41: tesla_energy_stats_collector.stream.v1.PowerwallCheck.checks:type_name -> google.protobuf.Value
+	26, // 42: tesla_energy_stats_collector.stream.v1.SiteInfo.timestamp:type_name -> google.protobuf.Timestamp
+	12, // 43: tesla_energy_stats_collector.stream.v1.SiteInfo.grid_code:type_name -> tesla_energy_stats_collector.stream.v1.GridCode
+	26, // 44: tesla_energy_stats_collector.stream.v1.Sitemaster.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 45: tesla_energy_stats_collector.stream.v1.Solar.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 46: tesla_energy_stats_collector.stream.v1.Status.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 47: tesla_energy_stats_collector.stream.v1.Status.start_time:type_name -> google.protobuf.Timestamp
+	28, // 48: tesla_energy_stats_collector.stream.v1.Status.uptime:type_name -> google.protobuf.Duration
+	27, // 49: tesla_energy_stats_collector.stream.v1.Status.leader:type_name -> google.protobuf.Value
+	27, // 50: tesla_energy_stats_collector.stream.v1.Status.followers:type_name -> google.protobuf.Value
+	26, // 51: tesla_energy_stats_collector.stream.v1.NetworkConnectionTests.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 52: tesla_energy_stats_collector.stream.v1.NetworkConnectionTests.inputs:type_name -> google.protobuf.Value
+	17, // 53: tesla_energy_stats_collector.stream.v1.NetworkConnectionTests.checks:type_name -> tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck
+	26, // 54: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck.start_time:type_name -> google.protobuf.Timestamp
+	26, // 55: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck.end_time:type_name -> google.protobuf.Timestamp
+	27, // 56: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck.results:type_name -> google.protobuf.Value
+	27, // 57: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck.debug:type_name -> google.protobuf.Value
+	27, // 58: tesla_energy_stats_collector.stream.v1.NetworkConnectionCheck.checks:type_name -> google.protobuf.Value
+	26, // 59: tesla_energy_stats_collector.stream.v1.SystemTesting.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 60: tesla_energy_stats_collector.stream.v1.SystemTesting.charge_tests:type_name -> google.protobuf.Value
+	27, // 61: tesla_energy_stats_collector.stream.v1.SystemTesting.meter_results:type_name -> google.protobuf.Value
+	27, // 62: tesla_energy_stats_collector.stream.v1.SystemTesting.inverter_results:type_name -> google.protobuf.Value
+	27, // 63: tesla_energy_stats_collector.stream.v1.SystemTesting.errors:type_name -> google.protobuf.Value
+	27, // 64: tesla_energy_stats_collector.stream.v1.SystemTesting.tests:type_name -> google.protobuf.Value
+	26, // 65: tesla_energy_stats_collector.stream.v1.UpdateStatus.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 66: tesla_energy_stats_collector.stream.v1.UpdateStatus.estimated_bytes_per_second:type_name -> google.protobuf.Value
+	26, // 67: tesla_energy_stats_collector.stream.v1.SystemStatus.timestamp:type_name -> google.protobuf.Timestamp
+	21, // 68: tesla_energy_stats_collector.stream.v1.SystemStatus.battery_blocks:type_name -> tesla_energy_stats_collector.stream.v1.BatteryBlock
+	22, // 69: tesla_energy_stats_collector.stream.v1.SystemStatus.grid_faults:type_name -> tesla_energy_stats_collector.stream.v1.GridFault
+	26, // 70: tesla_energy_stats_collector.stream.v1.SystemStatus.last_toggle_timestamp:type_name -> google.protobuf.Timestamp
+	23, // 71: tesla_energy_stats_collector.stream.v1.GridFault.decoded_alert:type_name -> tesla_energy_stats_collector.stream.v1.GridAlert
+	27, // 72: tesla_energy_stats_collector.stream.v1.GridAlert.value:type_name -> google.protobuf.Value
+	26, // 73: tesla_energy_stats_collector.stream.v1.SystemGridStatus.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 74: tesla_energy_stats_collector.stream.v1.SystemStateOfEnergy.timestamp:type_name -> google.protobuf.Timestamp
+	75, // [75:75] is the sub-list for method output_type
+	75, // [75:75] is the sub-list for method input_type
+	75, // [75:75] is the sub-list for extension type_name
+	75, // [75:75] is the sub-list for extension extendee
+	0,  // [0:75] is the sub-list for field type_name
+}
+
+func init() { file_snapshot_proto_init() }
+func file_snapshot_proto_init() {
+	if File_snapshot_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_snapshot_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_snapshot_proto_goTypes,
+		DependencyIndexes: file_snapshot_proto_depIdxs,
+		MessageInfos:      file_snapshot_proto_msgTypes,
+	}.Build()
+	File_snapshot_proto = out.File
+	file_snapshot_proto_rawDesc = nil
+	file_snapshot_proto_goTypes = nil
+	file_snapshot_proto_depIdxs = nil
+}
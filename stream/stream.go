@@ -0,0 +1,259 @@
+// Package stream publishes each poll as a versioned snapshot message to Kafka
+// or NATS JetStream for downstream consumers.
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"github.com/iwvelando/tesla-energy-stats-collector/stream/snapshotpb"
+	"google.golang.org/protobuf/proto"
+	"sync/atomic"
+	"time"
+)
+
+// SchemaVersion is bumped whenever the envelope, or the snapshot message in
+// snapshotpb, changes incompatibly
+const SchemaVersion = 1
+
+// publishTimeout bounds a single attempt to publish a batch
+const publishTimeout = 30 * time.Second
+
+// maxBackoff caps the wait between retries of a failed batch
+const maxBackoff = 30 * time.Second
+
+// Envelope is the message published for each poll in the json format
+type Envelope struct {
+	Version   int       `json:"version"`
+	GatewayID string    `json:"gateway_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Snapshot  model.Teg `json:"snapshot"`
+}
+
+type message struct {
+	key   string
+	id    string
+	value []byte
+}
+
+// publisher delivers a batch of messages, returning only once the broker has
+// acknowledged all of them
+type publisher interface {
+	publish(batch []message) error
+	close() error
+}
+
+// Stream queues snapshots and publishes them in batches, retrying failed
+// batches until they are acknowledged
+type Stream struct {
+	conf        config.Stream
+	contentType string
+	pub         publisher
+	queue       chan message
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	errCh   chan error
+	errRead int32
+}
+
+// Connect connects to the configured Kafka brokers or NATS server
+func Connect(conf *config.Configuration) (*Stream, error) {
+	if conf.Stream.BatchSize == 0 {
+		conf.Stream.BatchSize = 1
+	}
+	if conf.Stream.BatchTimeout == 0 {
+		conf.Stream.BatchTimeout = time.Second
+	}
+	if conf.Stream.BufferSize == 0 {
+		conf.Stream.BufferSize = 1000
+	}
+
+	s := &Stream{
+		conf:   conf.Stream,
+		queue:  make(chan message, conf.Stream.BufferSize),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+		errCh:  make(chan error),
+	}
+
+	switch conf.Stream.Format {
+	case "", "json":
+		s.contentType = "application/json"
+	case "protobuf":
+		s.contentType = "application/x-protobuf; messageType=" + string(proto.MessageName(&snapshotpb.Snapshot{}))
+	default:
+		return nil, fmt.Errorf("unsupported format %s, must be one of json or protobuf", conf.Stream.Format)
+	}
+
+	var err error
+	switch {
+	case len(conf.Stream.Kafka.Brokers) > 0 && conf.Stream.NATS.URL != "":
+		return nil, fmt.Errorf("only one of Kafka or NATS may be configured")
+	case len(conf.Stream.Kafka.Brokers) > 0:
+		s.pub, err = newKafka(conf.Stream, s.contentType)
+	case conf.Stream.NATS.URL != "":
+		s.pub, err = newNATS(conf.Stream, s.contentType)
+	default:
+		return nil, fmt.Errorf("no Kafka brokers or NATS URL configured")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// Publish encodes the snapshot and queues it; when the buffer is full this
+// blocks until there is room unless DropOnFull is set
+func (s *Stream) Publish(metrics model.Teg) error {
+	value, err := s.encode(metrics)
+	if err != nil {
+		return fmt.Errorf("error when encoding snapshot, %s", err)
+	}
+
+	m := message{
+		key:   metrics.Status.GatewayID,
+		id:    fmt.Sprintf("%s-%d", metrics.Status.GatewayID, metrics.Meters.Timestamp.UnixNano()),
+		value: value,
+	}
+
+	if s.conf.DropOnFull {
+		select {
+		case s.queue <- m:
+		default:
+			return fmt.Errorf("buffer of %d snapshots is full, dropped snapshot", cap(s.queue))
+		}
+		return nil
+	}
+
+	s.queue <- m
+	return nil
+}
+
+// encode renders the snapshot envelope as JSON, or as the typed Snapshot
+// message defined in snapshotpb/snapshot.proto
+func (s *Stream) encode(metrics model.Teg) ([]byte, error) {
+	if s.conf.Format == "protobuf" {
+		msg, err := snapshot(metrics)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(msg)
+	}
+
+	return json.Marshal(Envelope{
+		Version:   SchemaVersion,
+		GatewayID: metrics.Status.GatewayID,
+		Timestamp: metrics.Meters.Timestamp,
+		Snapshot:  metrics,
+	})
+}
+
+// Errors returns the channel of publish errors
+func (s *Stream) Errors() <-chan error {
+	atomic.StoreInt32(&s.errRead, 1)
+	return s.errCh
+}
+
+func (s *Stream) reportError(err error) {
+	if atomic.LoadInt32(&s.errRead) == 1 {
+		s.errCh <- err
+	}
+}
+
+// Close publishes the queued snapshots, making one final attempt for each
+// batch, and disconnects
+func (s *Stream) Close() {
+	close(s.stopCh)
+	<-s.doneCh
+	err := s.pub.close()
+	if err != nil {
+		s.reportError(fmt.Errorf("error when closing connection, %s", err))
+	}
+}
+
+func (s *Stream) run() {
+	defer close(s.doneCh)
+	for {
+		var batch []message
+		select {
+		case m := <-s.queue:
+			batch = append(batch, m)
+		case <-s.stopCh:
+			s.drain()
+			return
+		}
+
+		timer := time.NewTimer(s.conf.BatchTimeout)
+	collect:
+		for uint(len(batch)) < s.conf.BatchSize {
+			select {
+			case m := <-s.queue:
+				batch = append(batch, m)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		if !s.deliver(batch) {
+			s.drain()
+			return
+		}
+	}
+}
+
+// deliver publishes a batch, retrying with backoff until it succeeds; it
+// returns false if the stream was closed while retrying
+func (s *Stream) deliver(batch []message) bool {
+	backoff := time.Second
+	for {
+		err := s.pub.publish(batch)
+		if err == nil {
+			return true
+		}
+		s.reportError(fmt.Errorf("error when publishing %d snapshots, retrying in %s, %s", len(batch), backoff, err))
+
+		select {
+		case <-time.After(backoff):
+		case <-s.stopCh:
+			err = s.pub.publish(batch)
+			if err != nil {
+				s.reportError(fmt.Errorf("error when publishing %d snapshots, dropping them, %s", len(batch), err))
+			}
+			return false
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// drain makes one attempt to publish whatever is left in the queue
+func (s *Stream) drain() {
+	var batch []message
+	for {
+		select {
+		case m := <-s.queue:
+			batch = append(batch, m)
+			if uint(len(batch)) < s.conf.BatchSize {
+				continue
+			}
+		default:
+		}
+		if len(batch) == 0 {
+			return
+		}
+		err := s.pub.publish(batch)
+		if err != nil {
+			s.reportError(fmt.Errorf("error when publishing %d snapshots, dropping them, %s", len(batch), err))
+		}
+		batch = nil
+	}
+}
@@ -0,0 +1,216 @@
+package tariff
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/energy"
+	"sync"
+	"time"
+)
+
+// Amounts are the costs and credits of a period in the tariff's currency
+type Amounts struct {
+	ImportCost   float64 `json:"import_cost"`
+	ExportCredit float64 `json:"export_credit"`
+	FixedCharge  float64 `json:"fixed_charge"`
+	// ExportRetail is the export valued at the import price, as credited by
+	// retail net metering
+	ExportRetail float64 `json:"export_retail"`
+	// BaselineCost is what the load would have cost from the grid alone
+	BaselineCost float64 `json:"baseline_cost"`
+}
+
+// Net is what the period costs after export credits
+func (a Amounts) Net() float64 {
+	return a.ImportCost - a.ExportCredit + a.FixedCharge
+}
+
+// Savings is how much less the period cost than the baseline
+func (a Amounts) Savings() float64 {
+	return a.BaselineCost - a.Net()
+}
+
+func (a Amounts) add(b Amounts) Amounts {
+	return Amounts{
+		ImportCost:   a.ImportCost + b.ImportCost,
+		ExportCredit: a.ExportCredit + b.ExportCredit,
+		FixedCharge:  a.FixedCharge + b.FixedCharge,
+		ExportRetail: a.ExportRetail + b.ExportRetail,
+		BaselineCost: a.BaselineCost + b.BaselineCost,
+	}
+}
+
+// Cost is the cost of an interval of energy totals along with the running
+// costs of its day and month
+type Cost struct {
+	Time     time.Time
+	Date     string
+	Month    string
+	Final    bool
+	Price    Price
+	Interval Amounts
+	Daily    Amounts
+	Monthly  Amounts
+}
+
+// state is what the Engine persists across restarts
+type state struct {
+	Date    string  `json:"date"`
+	Month   string  `json:"month"`
+	Daily   Amounts `json:"daily"`
+	Monthly Amounts `json:"monthly"`
+}
+
+// Engine prices energy totals and accumulates the costs per day and month
+type Engine struct {
+	Tariff *Tariff
+
+	path         string
+	saveInterval time.Duration
+	saved        time.Time
+
+	// mu guards the day and month amounts, which Close saves while the poll
+	// loop may still be adding a price to them
+	mu     sync.Mutex
+	closed bool
+	state  state
+}
+
+// New compiles the tariff and loads the engine's state, if any was saved
+func New(conf *config.Configuration) (*Engine, error) {
+	t, err := Compile(conf)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Energy.SaveInterval == 0 {
+		conf.Energy.SaveInterval = time.Minute
+	}
+
+	e := &Engine{
+		Tariff:       t,
+		path:         conf.Tariff.StateFile,
+		saveInterval: conf.Energy.SaveInterval,
+	}
+
+	err = energy.LoadState(e.path, &e.state)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Close saves the state; totals arriving afterwards are ignored
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.save()
+}
+
+func (e *Engine) save() error {
+	return energy.SaveState(e.path, e.state)
+}
+
+// Price prices the energy of an interval; the interval is split wherever
+// the prices change, its energy shared in proportion to time, and the daily
+// charge is shared over the length of the day. The price returned is the one
+// in effect for most of the interval.
+func (t *Tariff) Price(totals energy.Totals) (Amounts, Price, error) {
+	loc := totals.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	imported := totals.Interval[energy.Site].Imported / 1000
+	exported := totals.Interval[energy.Site].Exported / 1000
+	load := totals.Interval[energy.Load].Imported / 1000
+
+	var a Amounts
+	var price Price
+	var longest time.Duration
+	start := totals.Start.In(loc)
+	end := totals.End.In(loc)
+	elapsed := end.Sub(start)
+	if elapsed <= 0 {
+		price = t.PriceAt(totals.Time().In(loc))
+	}
+	for start.Before(end) {
+		next := t.nextChange(start)
+		if next.After(end) {
+			next = end
+		}
+		portion := float64(next.Sub(start)) / float64(elapsed)
+		p := t.PriceAt(start)
+		a.ImportCost += imported * portion * p.Import
+		a.ExportCredit += exported * portion * p.Export
+		a.ExportRetail += exported * portion * p.Import
+		a.BaselineCost += load * portion * p.Import
+		if next.Sub(start) > longest {
+			longest = next.Sub(start)
+			price = p
+		}
+		start = next
+	}
+
+	day, err := time.ParseInLocation(energy.DateLayout, totals.Date, loc)
+	if err != nil {
+		return Amounts{}, Price{}, fmt.Errorf("error when parsing date %s, %s", totals.Date, err)
+	}
+	dayLength := day.AddDate(0, 0, 1).Sub(day)
+	a.FixedCharge = t.DailyCharge * float64(elapsed) / float64(dayLength)
+	a.BaselineCost += a.FixedCharge
+
+	return a, price, nil
+}
+
+// Update prices energy totals and returns their costs
+func (e *Engine) Update(totals []energy.Totals) ([]Cost, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, nil
+	}
+
+	var costs []Cost
+	for _, t := range totals {
+		amounts, price, err := e.Tariff.Price(t)
+		if err != nil {
+			return costs, err
+		}
+
+		if t.Date != e.state.Date {
+			e.state.Date = t.Date
+			e.state.Daily = Amounts{}
+		}
+		if t.Month != e.state.Month {
+			e.state.Month = t.Month
+			e.state.Monthly = Amounts{}
+		}
+		e.state.Daily = e.state.Daily.add(amounts)
+		e.state.Monthly = e.state.Monthly.add(amounts)
+
+		costs = append(costs, Cost{
+			Time:     t.Time(),
+			Date:     t.Date,
+			Month:    t.Month,
+			Final:    t.Final,
+			Price:    price,
+			Interval: amounts,
+			Daily:    e.state.Daily,
+			Monthly:  e.state.Monthly,
+		})
+	}
+
+	if time.Since(e.saved) >= e.saveInterval {
+		err := e.save()
+		if err != nil {
+			return costs, err
+		}
+		e.saved = time.Now()
+	}
+
+	return costs, nil
+}
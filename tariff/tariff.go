@@ -0,0 +1,200 @@
+// Package tariff prices the energy through the site meter at a time of use
+// tariff and compares it with what the load would cost without solar or a
+// battery.
+package tariff
+
+import (
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Day names periods may be restricted to
+var dayNames = map[string]bool{
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+	"weekday": true, "weekend": true, "holiday": true,
+}
+
+// Price is what energy costs and earns at a time, per kWh
+type Price struct {
+	Season string
+	Period string
+	Import float64
+	Export float64
+}
+
+type period struct {
+	name   string
+	days   map[string]bool
+	start  int
+	end    int
+	imp    float64
+	export float64
+}
+
+type season struct {
+	name    string
+	months  map[time.Month]bool
+	periods []period
+}
+
+// Tariff looks up the prices in effect at a time
+type Tariff struct {
+	Currency    string
+	DailyCharge float64
+
+	fallback   Price
+	holidays   map[string]bool
+	seasons    []season
+	boundaries []int
+}
+
+// parseClock reads HH:MM as minutes since midnight; 24:00 is the end of the
+// day
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("error when parsing time of day %s, %s", value, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Compile validates the tariff configuration
+func Compile(conf *config.Configuration) (*Tariff, error) {
+	t := &Tariff{
+		Currency:    conf.Tariff.Currency,
+		DailyCharge: conf.Tariff.DailyCharge,
+		fallback:    Price{Period: "default", Import: conf.Tariff.Import, Export: conf.Tariff.Export},
+		holidays:    map[string]bool{},
+	}
+
+	for _, holiday := range conf.Tariff.Holidays {
+		_, err := time.Parse("2006-01-02", holiday)
+		if err != nil {
+			_, err = time.Parse("01-02", holiday)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("holiday %s must be given as YYYY-MM-DD or MM-DD", holiday)
+		}
+		t.holidays[holiday] = true
+	}
+
+	boundaries := map[int]bool{0: true}
+	for _, s := range conf.Tariff.Seasons {
+		compiled := season{name: s.Name, months: map[time.Month]bool{}}
+		for _, month := range s.Months {
+			if month < 1 || month > 12 {
+				return nil, fmt.Errorf("season %s has invalid month %d", s.Name, month)
+			}
+			compiled.months[time.Month(month)] = true
+		}
+		for _, p := range s.Periods {
+			c := period{name: p.Name, days: map[string]bool{}, imp: p.Import, export: p.Export}
+			for _, day := range p.Days {
+				day = strings.ToLower(day)
+				if !dayNames[day] {
+					return nil, fmt.Errorf("period %s has invalid day %s", p.Name, day)
+				}
+				c.days[day] = true
+			}
+			if p.Start == "" {
+				p.Start = "00:00"
+			}
+			if p.End == "" {
+				p.End = "24:00"
+			}
+			var err error
+			c.start, err = parseClock(p.Start)
+			if err != nil {
+				return nil, err
+			}
+			c.end, err = parseClock(p.End)
+			if err != nil {
+				return nil, err
+			}
+			boundaries[c.start%(24*60)] = true
+			boundaries[c.end%(24*60)] = true
+			compiled.periods = append(compiled.periods, c)
+		}
+		t.seasons = append(t.seasons, compiled)
+	}
+	for b := range boundaries {
+		t.boundaries = append(t.boundaries, b)
+	}
+	sort.Ints(t.boundaries)
+
+	return t, nil
+}
+
+// days names the days a date counts as; a holiday only counts as a holiday
+func (t *Tariff) days(at time.Time) map[string]bool {
+	if t.holidays[at.Format("2006-01-02")] || t.holidays[at.Format("01-02")] {
+		return map[string]bool{"holiday": true}
+	}
+	weekday := strings.ToLower(at.Weekday().String()[:3])
+	kind := "weekday"
+	if at.Weekday() == time.Saturday || at.Weekday() == time.Sunday {
+		kind = "weekend"
+	}
+	return map[string]bool{weekday: true, kind: true}
+}
+
+// matches reports whether a period covers the minute of a day
+func (p period) matches(days map[string]bool, minute int) bool {
+	if len(p.days) > 0 {
+		found := false
+		for day := range days {
+			if p.days[day] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if p.start < p.end {
+		return minute >= p.start && minute < p.end
+	}
+	return minute >= p.start || minute < p.end
+}
+
+// PriceAt returns the prices in effect at a time, which must be in the site's
+// time zone; the first season and period which match win, else the
+// tariff's default prices apply
+func (t *Tariff) PriceAt(at time.Time) Price {
+	minute := at.Hour()*60 + at.Minute()
+	days := t.days(at)
+	for _, s := range t.seasons {
+		if len(s.months) > 0 && !s.months[at.Month()] {
+			continue
+		}
+		for _, p := range s.periods {
+			if p.matches(days, minute) {
+				return Price{Season: s.name, Period: p.name, Import: p.imp, Export: p.export}
+			}
+		}
+	}
+	return t.fallback
+}
+
+// nextChange returns the next time after at, which must be in the site's
+// time zone, at which the prices may change
+func (t *Tariff) nextChange(at time.Time) time.Time {
+	minute := at.Hour()*60 + at.Minute()
+	y, m, d := at.Date()
+	for _, b := range t.boundaries {
+		if b > minute {
+			next := time.Date(y, m, d, b/60, b%60, 0, 0, at.Location())
+			if next.After(at) {
+				return next
+			}
+		}
+	}
+	return time.Date(y, m, d+1, 0, 0, 0, 0, at.Location())
+}
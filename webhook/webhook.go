@@ -0,0 +1,207 @@
+// Package webhook posts snapshots to HTTP endpoints using payloads rendered
+// from configured templates.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/iwvelando/tesla-energy-stats-collector/config"
+	"github.com/iwvelando/tesla-energy-stats-collector/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// defaultTemplate posts the whole snapshot as JSON
+const defaultTemplate = `{{ json . }}`
+
+// funcs are available to payload templates
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"unix": func(t time.Time) int64 {
+		return t.Unix()
+	},
+	"rfc3339": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}
+
+type hook struct {
+	conf     config.Webhook
+	template *template.Template
+	client   *http.Client
+
+	mu       sync.Mutex
+	lastSent time.Time
+	inFlight bool
+}
+
+// Dispatcher renders each snapshot for every webhook and delivers the
+// payloads in the background
+type Dispatcher struct {
+	hooks []*hook
+	wg    sync.WaitGroup
+
+	errCh   chan error
+	errRead int32
+}
+
+// New parses the webhook templates
+func New(conf *config.Configuration) (*Dispatcher, error) {
+	d := &Dispatcher{
+		errCh: make(chan error),
+	}
+
+	for i, hookConf := range conf.Webhooks {
+		if hookConf.URL == "" {
+			return nil, fmt.Errorf("webhook %d has no URL", i)
+		}
+		if hookConf.Method == "" {
+			hookConf.Method = http.MethodPost
+		}
+		if hookConf.Template == "" {
+			hookConf.Template = defaultTemplate
+		}
+		if hookConf.SignatureHeader == "" {
+			hookConf.SignatureHeader = "X-Signature-256"
+		}
+		if hookConf.Timeout == 0 {
+			hookConf.Timeout = 10 * time.Second
+		}
+
+		tmpl, err := template.New(hookConf.URL).Funcs(funcs).Parse(hookConf.Template)
+		if err != nil {
+			return nil, fmt.Errorf("error when parsing template for %s, %s", hookConf.URL, err)
+		}
+
+		d.hooks = append(d.hooks, &hook{
+			conf:     hookConf,
+			template: tmpl,
+			client: &http.Client{
+				Timeout: hookConf.Timeout,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: hookConf.SkipVerifySsl},
+				},
+			},
+		})
+	}
+
+	return d, nil
+}
+
+// Send renders the payload for every webhook which is not throttled and posts
+// it in the background; a webhook still retrying a previous payload is skipped
+func (d *Dispatcher) Send(metrics model.Teg) {
+	now := time.Now()
+	for _, h := range d.hooks {
+		h.mu.Lock()
+		if h.inFlight || now.Sub(h.lastSent) < h.conf.MinInterval {
+			h.mu.Unlock()
+			continue
+		}
+
+		var body bytes.Buffer
+		err := h.template.Execute(&body, metrics)
+		if err != nil {
+			h.mu.Unlock()
+			d.reportError(fmt.Errorf("error when rendering template for %s, %s", h.conf.URL, err))
+			continue
+		}
+
+		h.inFlight = true
+		h.lastSent = now
+		h.mu.Unlock()
+
+		d.wg.Add(1)
+		go func(h *hook, payload []byte) {
+			defer d.wg.Done()
+			err := h.deliver(payload)
+			if err != nil {
+				d.reportError(err)
+			}
+			h.mu.Lock()
+			h.inFlight = false
+			h.mu.Unlock()
+		}(h, body.Bytes())
+	}
+}
+
+// Wait blocks until in-flight deliveries have finished
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Errors returns the channel of rendering and delivery errors
+func (d *Dispatcher) Errors() <-chan error {
+	atomic.StoreInt32(&d.errRead, 1)
+	return d.errCh
+}
+
+func (d *Dispatcher) reportError(err error) {
+	if atomic.LoadInt32(&d.errRead) == 1 {
+		d.errCh <- err
+	}
+}
+
+// deliver sends the payload, retrying connection errors, 429 and 5xx
+// responses with backoff
+func (h *hook) deliver(payload []byte) error {
+	backoff := time.Second
+	var err error
+	for attempt := uint(0); ; attempt++ {
+		var retry bool
+		retry, err = h.post(payload)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= h.conf.Retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return fmt.Errorf("error when posting to %s, %s", h.conf.URL, err)
+}
+
+func (h *hook) post(payload []byte) (bool, error) {
+	req, err := http.NewRequest(h.conf.Method, h.conf.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	if h.conf.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(h.conf.Secret))
+		mac.Write(payload)
+		req.Header.Set(h.conf.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("expected 2xx HTTP status code but got %d; raw body %s", resp.StatusCode, body)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+
+	return false, nil
+}